@@ -23,6 +23,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
+	"golang.org/x/sys/unix"
 )
 
 var deleteCommand = &cli.Command{
@@ -74,7 +75,7 @@ status of "ubuntu01" as "stopped" the following will delete resources held for
 			return err
 		}
 		if cmd.Bool("force") {
-			err := unikontainer.Kill()
+			err := unikontainer.Kill(unix.SIGKILL, true)
 			if err != nil {
 				return err
 			}
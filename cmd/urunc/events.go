@@ -0,0 +1,113 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	runctypes "github.com/opencontainers/runc/types"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+var eventsCommand = &cli.Command{
+	Name:  "events",
+	Usage: "display container events such as cpu, memory, network and IO usage statistics",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container.`,
+	Description: `The events command displays information about the container. By default the
+information is displayed once every 5 seconds.
+
+The statistics follow the format of runc. The CPU, memory, pids and IO
+statistics refer to the cgroup of the monitor process and the network
+statistics to the tap devices of the container. Any statistics that the
+monitor reports about the guest are placed under "monitor".`,
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "interval",
+			Value: 5 * time.Second,
+			Usage: "set the stats collection interval",
+		},
+		&cli.BoolFlag{
+			Name:  "stats",
+			Usage: "display the container's stats then exit",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		logrus.WithField("command", "EVENTS").WithField("args", os.Args).Debug("urunc INVOKED")
+		if err := checkArgs(cmd, 1, exactArgs); err != nil {
+			return err
+		}
+		interval := cmd.Duration("interval")
+		if interval <= 0 {
+			return errors.New("duration interval must be greater than 0")
+		}
+
+		// get Unikontainer data from state.json
+		unikontainer, err := getUnikontainer(cmd)
+		if err != nil {
+			return err
+		}
+		err = unikontainer.UpdateStatus()
+		if err != nil {
+			return fmt.Errorf("failed to update the status of the container: %w", err)
+		}
+		if unikontainer.State.Status == specs.StateStopped {
+			return fmt.Errorf("container with id %s is not running", unikontainer.State.ID)
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		if cmd.Bool("stats") {
+			stats, err := unikontainer.Stats()
+			if err != nil {
+				return err
+			}
+			return enc.Encode(&runctypes.Event{Type: "stats", ID: unikontainer.State.ID, Data: stats})
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			err = unikontainer.UpdateStatus()
+			if err != nil {
+				return fmt.Errorf("failed to update the status of the container: %w", err)
+			}
+			if unikontainer.State.Status == specs.StateStopped {
+				return nil
+			}
+			stats, err := unikontainer.Stats()
+			if err != nil {
+				logrus.Error(err)
+				continue
+			}
+			err = enc.Encode(&runctypes.Event{Type: "stats", ID: unikontainer.State.ID, Data: stats})
+			if err != nil {
+				logrus.Error(err)
+			}
+		}
+	},
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/creack/pty"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"github.com/urunc-dev/urunc/pkg/unikontainers"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/agent"
+	"golang.org/x/sys/unix"
+)
+
+// execDetachedEnv marks the urunc process that a detached exec spawns to
+// serve the session with the guest agent.
+const execDetachedEnv = "_URUNC_EXEC_DETACHED"
+
+// Stop parsing flags after the container id, so that the flags of the
+// command to execute are not treated as urunc flags.
+var execStopOnNthArg = 1
+
+var execCommand = &cli.Command{
+	Name:  "exec",
+	Usage: "execute new process inside the container",
+	ArgsUsage: `<container-id> <command> [command options]  || -p process.json <container-id>
+
+Where "<container-id>" is the name for the instance of the container and
+"<command>" is the command to be executed in the container.
+"<command>" can't be empty unless a "-p" flag provided.
+
+EXAMPLE:
+For example, if the container is configured to run the linux ps command the
+following will output a list of processes running in the container:
+
+	# urunc exec <container-id> ps`,
+	Description: `The exec command executes a new process inside the guest of the container,
+through the urunit agent. Therefore, it is only supported for Linux guests
+which use urunit as init and run over QEMU or Firecracker.`,
+	StopOnNthArg: &execStopOnNthArg,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "console-socket",
+			Usage: "path to an AF_UNIX socket which will receive a file descriptor referencing the master end of the console's pseudoterminal",
+		},
+		&cli.StringFlag{
+			Name:  "cwd",
+			Usage: "current working directory in the container",
+		},
+		&cli.StringSliceFlag{
+			Name:    "env",
+			Aliases: []string{"e"},
+			Usage:   "set environment variables",
+		},
+		&cli.BoolFlag{
+			Name:    "tty",
+			Aliases: []string{"t"},
+			Usage:   "allocate a pseudo-TTY",
+		},
+		&cli.StringFlag{
+			Name:    "user",
+			Aliases: []string{"u"},
+			Usage:   "UID (format: <uid>[:<gid>])",
+		},
+		&cli.StringFlag{
+			Name:    "process",
+			Aliases: []string{"p"},
+			Usage:   "path to the process.json",
+		},
+		&cli.BoolFlag{
+			Name:    "detach",
+			Aliases: []string{"d"},
+			Usage:   "detach from the container's process",
+		},
+		&cli.StringFlag{
+			Name:  "pid-file",
+			Usage: "specify the file to write the process id to",
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		logrus.WithField("command", "EXEC").WithField("args", os.Args).Debug("urunc INVOKED")
+		if err := checkArgs(cmd, 1, minArgs); err != nil {
+			return err
+		}
+
+		// get Unikontainer data from state.json
+		unikontainer, err := getUnikontainer(cmd)
+		if err != nil {
+			return err
+		}
+		proc, err := getExecProcess(cmd, unikontainer)
+		if err != nil {
+			return err
+		}
+
+		if os.Getenv(execDetachedEnv) != "" {
+			return execProcess(unikontainer, proc, os.Stdin, os.Stdout, os.Stderr)
+		}
+
+		stdin, stdout, stderr := os.Stdin, os.Stdout, os.Stderr
+		consoleSocket := cmd.String("console-socket")
+		if proc.Terminal && consoleSocket != "" {
+			tty, err := setupConsole(consoleSocket)
+			if err != nil {
+				return err
+			}
+			defer tty.Close()
+			stdin, stdout, stderr = tty, tty, tty
+		}
+
+		if cmd.Bool("detach") {
+			return detachExec(cmd.String("pid-file"), stdin, stdout, stderr)
+		}
+		if pidFile := cmd.String("pid-file"); pidFile != "" {
+			err = writePidFile(pidFile, os.Getpid())
+			if err != nil {
+				return err
+			}
+		}
+		if proc.Terminal && consoleSocket == "" && stdin == os.Stdin {
+			restore, err := setRawTerminal(os.Stdin)
+			if err == nil {
+				defer restore()
+			}
+		}
+
+		return execProcess(unikontainer, proc, stdin, stdout, stderr)
+	},
+}
+
+// getExecProcess builds the process to execute in the guest, either from
+// the process.json file or from the command line. Any value that is not
+// specified falls back to the one of the container's process.
+func getExecProcess(cmd *cli.Command, unikontainer *unikontainers.Unikontainer) (agent.Process, error) {
+	var proc agent.Process
+	if cntrProc := unikontainer.Spec.Process; cntrProc != nil {
+		proc.Env = cntrProc.Env
+		proc.Cwd = cntrProc.Cwd
+		proc.UID = cntrProc.User.UID
+		proc.GID = cntrProc.User.GID
+	}
+
+	if path := cmd.String("process"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return proc, fmt.Errorf("failed to read process file: %w", err)
+		}
+		var specProc specs.Process
+		err = json.Unmarshal(data, &specProc)
+		if err != nil {
+			return proc, fmt.Errorf("failed to parse process file: %w", err)
+		}
+		proc.Args = specProc.Args
+		proc.Env = specProc.Env
+		proc.Terminal = specProc.Terminal
+		proc.UID = specProc.User.UID
+		proc.GID = specProc.User.GID
+		if specProc.Cwd != "" {
+			proc.Cwd = specProc.Cwd
+		}
+	} else {
+		proc.Args = cmd.Args().Tail()
+		proc.Terminal = cmd.Bool("tty")
+	}
+	if len(proc.Args) == 0 {
+		return proc, fmt.Errorf("exec args cannot be empty")
+	}
+
+	proc.Env = append(proc.Env, cmd.StringSlice("env")...)
+	if cwd := cmd.String("cwd"); cwd != "" {
+		proc.Cwd = cwd
+	}
+	if user := cmd.String("user"); user != "" {
+		uidStr, gidStr, hasGID := strings.Cut(user, ":")
+		uid, err := strconv.ParseUint(uidStr, 10, 32)
+		if err != nil {
+			return proc, fmt.Errorf("invalid uid %q: %w", uidStr, err)
+		}
+		proc.UID = uint32(uid)
+		if hasGID {
+			gid, err := strconv.ParseUint(gidStr, 10, 32)
+			if err != nil {
+				return proc, fmt.Errorf("invalid gid %q: %w", gidStr, err)
+			}
+			proc.GID = uint32(gid)
+		}
+	}
+
+	return proc, nil
+}
+
+// execProcess executes the process in the guest and exits with the exit
+// status of the process.
+func execProcess(unikontainer *unikontainers.Unikontainer, proc agent.Process, stdin, stdout, stderr *os.File) error {
+	code, err := unikontainer.ExecProcess(proc, agent.Stdio{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return err
+	}
+	os.Exit(code)
+
+	return nil
+}
+
+// detachExec spawns a new urunc process which serves the exec session
+// with the guest agent, writes its pid in pidFile and returns without
+// waiting for it. The exit status of the spawned process is the one of
+// the process in the guest.
+func detachExec(pidFile string, stdin, stdout, stderr *os.File) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	child := exec.Command(self, os.Args[1:]...) //nolint: gosec
+	child.Env = append(os.Environ(), execDetachedEnv+"=1")
+	child.Stdin = stdin
+	child.Stdout = stdout
+	child.Stderr = stderr
+	child.SysProcAttr = &unix.SysProcAttr{Setsid: true}
+	err = child.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start exec process: %w", err)
+	}
+	if pidFile != "" {
+		err = writePidFile(pidFile, child.Process.Pid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return child.Process.Release()
+}
+
+// setupConsole creates a new pseudoterminal, sends its master end to the
+// consoleSocket and returns its slave end.
+func setupConsole(consoleSocket string) (*os.File, error) {
+	ptm, pts, err := pty.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pty: %w", err)
+	}
+	defer ptm.Close()
+
+	conn, err := net.Dial("unix", consoleSocket)
+	if err != nil {
+		pts.Close()
+		return nil, fmt.Errorf("failed to dial console socket: %w", err)
+	}
+	defer conn.Close()
+
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		pts.Close()
+		return nil, fmt.Errorf("failed to cast unix socket")
+	}
+
+	// Send file descriptor over socket.
+	oob := unix.UnixRights(int(ptm.Fd()))
+	_, _, err = uc.WriteMsgUnix([]byte(ptm.Name()), oob, nil)
+	if err != nil {
+		pts.Close()
+		return nil, fmt.Errorf("failed to send PTY file descriptor over socket: %w", err)
+	}
+
+	// The guest allocates its own terminal for the process, hence the
+	// pty must pass the input and the output unmodified.
+	_, err = setRawTerminal(pts)
+	if err != nil {
+		pts.Close()
+		return nil, fmt.Errorf("failed to set the pty in raw mode: %w", err)
+	}
+
+	return pts, nil
+}
+
+// setRawTerminal puts the terminal in raw mode, since the guest allocates
+// its own terminal for the process. It returns a function which restores
+// the previous mode of the terminal.
+func setRawTerminal(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+	oldState := *termios
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	err = unix.IoctlSetTermios(fd, unix.TCSETS, termios)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(fd, unix.TCSETS, &oldState)
+	}, nil
+}
+
+// writePidFile writes the pid to path atomically.
+func writePidFile(path string, pid int) error {
+	tmpPath := path + ".tmp"
+	err := os.WriteFile(tmpPath, []byte(strconv.Itoa(pid)), 0o644) //nolint: gosec
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
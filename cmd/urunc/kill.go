@@ -16,11 +16,15 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
+	"golang.org/x/sys/unix"
 )
 
 var killCommand = &cli.Command{
@@ -35,7 +39,12 @@ EXAMPLE:
 For example, if the container id is "ubuntu01" the following will send a "KILL"
 signal to the init process of the "ubuntu01" container:
 
-	# urunc kill ubuntu01 KILL`,
+	# urunc kill ubuntu01 KILL
+
+Since the init process of a unikernel container is the monitor, the signal
+is mapped to the respective monitor action. SIGTERM, SIGINT and SIGPWR ask
+the guest to shut down gracefully, SIGKILL terminates the monitor immediately
+and any other signal is forwarded to the monitor process.`,
 	Flags: []cli.Flag{
 		&cli.BoolFlag{
 			Name:    "all",
@@ -54,11 +63,40 @@ signal to the init process of the "ubuntu01" container:
 			return err
 		}
 
+		sigStr := cmd.Args().Get(1)
+		if sigStr == "" {
+			sigStr = "SIGTERM"
+		}
+		sig, err := parseSignal(sigStr)
+		if err != nil {
+			return err
+		}
+
 		// get Unikontainer data from state.json
 		unikontainer, err := getUnikontainer(cmd)
 		if err != nil {
 			return err
 		}
-		return unikontainer.Kill()
+		return unikontainer.Kill(sig, cmd.Bool("all"))
 	},
 }
+
+// parseSignal parses a signal given either as a number or as a name, with
+// or without the "SIG" prefix.
+// Taken from runc:
+// https://github.com/opencontainers/runc/blob/v1.2.8/kill.go#L63
+func parseSignal(rawSignal string) (unix.Signal, error) {
+	s, err := strconv.Atoi(rawSignal)
+	if err == nil {
+		return unix.Signal(s), nil
+	}
+	sig := strings.ToUpper(rawSignal)
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+	signal := unix.SignalNum(sig)
+	if signal == 0 {
+		return -1, fmt.Errorf("unknown signal %q", rawSignal)
+	}
+	return signal, nil
+}
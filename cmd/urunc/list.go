@@ -0,0 +1,136 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"github.com/urunc-dev/urunc/pkg/unikontainers"
+)
+
+const formatOptions = `table or json`
+
+// containerState represents the information of a unikernel container
+// that gets displayed by the list command.
+type containerState struct {
+	ID            string `json:"id"`
+	Pid           int    `json:"pid"`
+	Status        string `json:"status"`
+	Bundle        string `json:"bundle"`
+	Hypervisor    string `json:"hypervisor"`
+	UnikernelType string `json:"unikernelType"`
+}
+
+var listCommand = &cli.Command{
+	Name:  "list",
+	Usage: "lists containers started by urunc with the given root",
+	ArgsUsage: `
+
+Where the given root is specified via the global option "--root"
+(default: "/run/urunc").
+
+EXAMPLE 1:
+To list containers created via the default "--root":
+	# urunc list
+
+EXAMPLE 2:
+To list containers created using a non-default value for "--root":
+	# urunc --root value list`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Value:   "table",
+			Usage:   `select one of: ` + formatOptions,
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "display only container IDs",
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		logrus.WithField("command", "LIST").WithField("args", os.Args).Debug("urunc INVOKED")
+		if err := checkArgs(cmd, 0, exactArgs); err != nil {
+			return err
+		}
+
+		states, err := getContainerStates(cmd.String("root"))
+		if err != nil {
+			return err
+		}
+
+		if cmd.Bool("quiet") {
+			for _, s := range states {
+				fmt.Println(s.ID)
+			}
+			return nil
+		}
+
+		switch cmd.String("format") {
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 12, 1, 3, ' ', 0)
+			fmt.Fprint(w, "ID\tPID\tSTATUS\tBUNDLE\tHYPERVISOR\tUNIKERNEL\n")
+			for _, s := range states {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n",
+					s.ID,
+					s.Pid,
+					s.Status,
+					s.Bundle,
+					s.Hypervisor,
+					s.UnikernelType)
+			}
+			return w.Flush()
+		case "json":
+			return json.NewEncoder(os.Stdout).Encode(states)
+		default:
+			return errors.New("invalid format option")
+		}
+	},
+}
+
+// getContainerStates loads the state of every unikernel container under
+// rootDir, making sure that the reported status is up to date.
+func getContainerStates(rootDir string) ([]containerState, error) {
+	ukontainers, err := unikontainers.List(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]containerState, 0, len(ukontainers))
+	for _, u := range ukontainers {
+		err = u.UpdateStatus()
+		if err != nil {
+			logrus.WithError(err).Warnf("failed to update the status of %s", u.State.ID)
+		}
+		states = append(states, containerState{
+			ID:            u.State.ID,
+			Pid:           u.State.Pid,
+			Status:        string(u.State.Status),
+			Bundle:        u.State.Bundle,
+			Hypervisor:    u.Hypervisor(),
+			UnikernelType: u.UnikernelType(),
+		})
+	}
+
+	return states, nil
+}
@@ -111,11 +111,17 @@ func main() {
 		Commands: []*cli.Command{
 			createCommand,
 			deleteCommand,
+			eventsCommand,
+			execCommand,
 			killCommand,
+			listCommand,
+			pauseCommand,
+			psCommand,
+			resumeCommand,
 			runCommand,
-			// specCommand,
+			specCommand,
 			startCommand,
-			// stateCommand,
+			stateCommand,
 		},
 		Before: func(_ context.Context, cmd *cli.Command) (context.Context, error) {
 			if err := reviseRootDir(cmd); err != nil {
@@ -0,0 +1,121 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+var psCommand = &cli.Command{
+	Name:  "ps",
+	Usage: "ps displays the processes running inside a container",
+	ArgsUsage: `<container-id> [ps options]
+
+Where "<container-id>" is the name for the instance of the container and
+"[ps options]" are the options passed to ps(1) (default: "-ef").
+
+The processes of a unikernel container are the monitor process and any
+helper processes spawned for the container (e.g. virtiofsd).`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Value:   "table",
+			Usage:   `select one of: ` + formatOptions,
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		logrus.WithField("command", "PS").WithField("args", os.Args).Debug("urunc INVOKED")
+		if err := checkArgs(cmd, 1, minArgs); err != nil {
+			return err
+		}
+
+		// get Unikontainer data from state.json
+		unikontainer, err := getUnikontainer(cmd)
+		if err != nil {
+			return err
+		}
+
+		pids, err := unikontainer.Processes()
+		if err != nil {
+			return err
+		}
+
+		switch cmd.String("format") {
+		case "table":
+		case "json":
+			return json.NewEncoder(os.Stdout).Encode(pids)
+		default:
+			return errors.New("invalid format option")
+		}
+
+		// Remove the container id from the arguments and keep
+		// only the options for ps
+		psArgs := cmd.Args().Tail()
+		if len(psArgs) == 0 {
+			psArgs = []string{"-ef"}
+		}
+
+		output, err := exec.Command("ps", psArgs...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+
+		lines := strings.Split(string(output), "\n")
+		pidIndex, err := getPidIndex(lines[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(lines[0])
+		for _, line := range lines[1:] {
+			fields := strings.Fields(line)
+			if len(fields) <= pidIndex {
+				continue
+			}
+			p, err := strconv.Atoi(fields[pidIndex])
+			if err != nil {
+				return fmt.Errorf("unable to parse pid: %w", err)
+			}
+			if slices.Contains(pids, p) {
+				fmt.Println(line)
+			}
+		}
+
+		return nil
+	},
+}
+
+// getPidIndex returns the index of the PID column in the header of ps output
+func getPidIndex(title string) (int, error) {
+	for i, name := range strings.Fields(title) {
+		if name == "PID" {
+			return i, nil
+		}
+	}
+
+	return -1, errors.New("couldn't find PID field in ps output")
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/specconv"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"github.com/urunc-dev/urunc/pkg/unikontainers"
+)
+
+var specCommand = &cli.Command{
+	Name:      "spec",
+	Usage:     "create a new specification file",
+	ArgsUsage: "",
+	Description: `The spec command creates the new specification file named "` + specConfig + `" for
+the bundle.
+
+The spec generated is just a starter file. Editing of the spec is required to
+achieve desired results.
+
+If any of the unikernel flags is given, the generated spec also contains the
+urunc annotations that describe how to execute the unikernel. In that case,
+the "--unikernel-type", "--hypervisor" and "--binary" flags are mandatory and
+the args of the process are set to the value of "--cmdline".
+
+EXAMPLE:
+  To create a bundle for a Unikraft unikernel running on top of QEMU:
+
+    mkdir -p bundle/rootfs
+    cp kernel bundle/rootfs/
+    urunc spec --bundle bundle --unikernel-type unikraft --hypervisor qemu \
+        --binary /kernel --cmdline "kernel -- hello"
+    urunc run --bundle bundle container1`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "bundle",
+			Aliases: []string{"b"},
+			Value:   "",
+			Usage:   "path to the root of the bundle directory",
+		},
+		&cli.StringFlag{
+			Name:  "unikernel-type",
+			Usage: "the type of the unikernel (e.g. unikraft, rumprun, linux)",
+		},
+		&cli.StringFlag{
+			Name:  "unikernel-version",
+			Usage: "the version of the unikernel framework",
+		},
+		&cli.StringFlag{
+			Name:  "hypervisor",
+			Usage: "the monitor that will execute the unikernel (e.g. qemu, firecracker, hvt)",
+		},
+		&cli.StringFlag{
+			Name:  "binary",
+			Usage: "the path of the unikernel binary inside the rootfs",
+		},
+		&cli.StringFlag{
+			Name:  "cmdline",
+			Usage: "the command line of the unikernel",
+		},
+		&cli.StringFlag{
+			Name:  "initrd",
+			Usage: "the path of the initrd inside the rootfs",
+		},
+		&cli.StringFlag{
+			Name:  "block",
+			Usage: "the path of a block image inside the rootfs",
+		},
+		&cli.StringFlag{
+			Name:  "block-mount-point",
+			Usage: "the mount point of the block image inside the unikernel",
+		},
+		&cli.BoolFlag{
+			Name:  "mount-rootfs",
+			Usage: "use the container's rootfs as the rootfs of the unikernel",
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		logrus.WithField("command", "SPEC").WithField("args", os.Args).Debug("urunc INVOKED")
+		if err := checkArgs(cmd, 0, exactArgs); err != nil {
+			return err
+		}
+
+		spec := specconv.Example()
+
+		if isUnikernelSpec(cmd) {
+			conf := &unikontainers.UnikernelConfig{
+				UnikernelType:    cmd.String("unikernel-type"),
+				UnikernelVersion: cmd.String("unikernel-version"),
+				UnikernelCmd:     cmd.String("cmdline"),
+				UnikernelBinary:  cmd.String("binary"),
+				Hypervisor:       cmd.String("hypervisor"),
+				Initrd:           cmd.String("initrd"),
+				Block:            cmd.String("block"),
+				BlkMntPoint:      cmd.String("block-mount-point"),
+			}
+			if cmd.Bool("mount-rootfs") {
+				conf.MountRootfs = "true"
+			}
+			annotations, err := conf.Annotations()
+			if err != nil {
+				return err
+			}
+			spec.Annotations = annotations
+			// The args of the process take precedence over the cmdline
+			// annotation and there is no terminal to attach to a unikernel.
+			// Container engines reject empty args, so without a cmdline we
+			// keep the args of the example spec, which need editing.
+			if args := strings.Fields(conf.UnikernelCmd); len(args) > 0 {
+				spec.Process.Args = args
+			}
+			spec.Process.Terminal = false
+		}
+
+		specPath := filepath.Join(cmd.String("bundle"), specConfig)
+		_, err := os.Stat(specPath)
+		if err == nil {
+			return fmt.Errorf("file %s exists. Remove it first", specPath)
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		data, err := json.MarshalIndent(spec, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(specPath, data, 0o666)
+	},
+}
+
+// isUnikernelSpec returns true if any of the flags that describe the
+// unikernel was set.
+func isUnikernelSpec(cmd *cli.Command) bool {
+	for _, flag := range []string{
+		"unikernel-type",
+		"unikernel-version",
+		"hypervisor",
+		"binary",
+		"cmdline",
+		"initrd",
+		"block",
+		"block-mount-point",
+		"mount-rootfs",
+	} {
+		if cmd.IsSet(flag) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agent implements the host side of the protocol that urunc uses to
+// execute processes inside a guest, through an agent running in the guest
+// (e.g. urunit).
+//
+// The host connects to the agent and sends a FrameProcess frame with the
+// JSON encoded Process to execute. Afterwards, the host sends the stdin of
+// the process in FrameStdin frames, with an empty FrameStdin frame denoting
+// EOF. The agent streams back the output of the process in FrameStdout and
+// FrameStderr frames and terminates the session with either a FrameExit frame
+// carrying the exit status of the process or a FrameError frame carrying the
+// reason that the process could not be executed.
+//
+// Every frame consists of a 1-byte type, a 4-byte big endian length and the
+// payload.
+package agent
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultVSockPort is the vsock port that the agent listens on
+const DefaultVSockPort uint32 = 1025
+
+// CmdlineVSockPort is the kernel command line parameter that informs the
+// agent about the vsock port to listen on
+const CmdlineVSockPort = "URUNIT_AGENT_VSOCK_PORT"
+
+// GuestSupport reports whether the init of the guest implements the agent
+// side of the protocol. urunit does not implement it yet, so urunc does not
+// set up the agent and urunc exec fails until it does.
+const GuestSupport = false
+
+// maxFrameSize is the maximum size of the payload of a frame
+const maxFrameSize = 1 << 20
+
+type FrameType uint8
+
+const (
+	FrameProcess FrameType = iota // host -> guest: JSON encoded Process
+	FrameStdin                    // host -> guest: stdin data, empty for EOF
+	FrameStdout                   // guest -> host: stdout data
+	FrameStderr                   // guest -> host: stderr data
+	FrameExit                     // guest -> host: 4-byte big endian exit status
+	FrameError                    // guest -> host: error message
+)
+
+var ErrFrameTooLarge = errors.New("agent frame exceeds maximum size")
+var ErrUnexpectedFrame = errors.New("unexpected agent frame")
+
+// Process describes a process to execute inside the guest
+type Process struct {
+	Args     []string `json:"args"`
+	Env      []string `json:"env,omitempty"`
+	Cwd      string   `json:"cwd,omitempty"`
+	UID      uint32   `json:"uid"`
+	GID      uint32   `json:"gid"`
+	Terminal bool     `json:"terminal,omitempty"`
+}
+
+// Stdio holds the streams of the host which get connected to the process
+type Stdio struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// WriteFrame writes a single frame with the given type and payload to w
+func WriteFrame(w io.Writer, t FrameType, payload []byte) error {
+	if len(payload) > maxFrameSize {
+		return ErrFrameTooLarge
+	}
+	hdr := make([]byte, 5, 5+len(payload))
+	hdr[0] = byte(t)
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(payload))) //nolint: gosec
+	_, err := w.Write(append(hdr, payload...))
+	return err
+}
+
+// ReadFrame reads a single frame from r
+func ReadFrame(r io.Reader) (FrameType, []byte, error) {
+	var hdr [5]byte
+	_, err := io.ReadFull(r, hdr[:])
+	if err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(hdr[1:])
+	if size > maxFrameSize {
+		return 0, nil, ErrFrameTooLarge
+	}
+	payload := make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return FrameType(hdr[0]), payload, nil
+}
+
+// frameWriter serializes the frames written by multiple goroutines
+type frameWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (fw *frameWriter) write(t FrameType, payload []byte) error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return WriteFrame(fw.w, t, payload)
+}
+
+// Exec executes the given process through the agent connection conn and
+// connects the process to stdio. It returns the exit status of the process,
+// once the agent reports that the process has exited.
+func Exec(conn io.ReadWriter, proc Process, stdio Stdio) (int, error) {
+	if len(proc.Args) == 0 {
+		return -1, errors.New("no process arguments were specified")
+	}
+	data, err := json.Marshal(proc)
+	if err != nil {
+		return -1, err
+	}
+	fw := &frameWriter{w: conn}
+	err = fw.write(FrameProcess, data)
+	if err != nil {
+		return -1, fmt.Errorf("failed to send process to agent: %w", err)
+	}
+
+	if stdio.Stdin != nil {
+		go forwardStdin(fw, stdio.Stdin)
+	} else {
+		_ = fw.write(FrameStdin, nil)
+	}
+
+	for {
+		t, payload, err := ReadFrame(conn)
+		if err != nil {
+			return -1, fmt.Errorf("failed to read from agent: %w", err)
+		}
+		switch t {
+		case FrameStdout:
+			err = writeOutput(stdio.Stdout, payload)
+		case FrameStderr:
+			err = writeOutput(stdio.Stderr, payload)
+		case FrameExit:
+			if len(payload) != 4 {
+				return -1, fmt.Errorf("%w: malformed exit frame", ErrUnexpectedFrame)
+			}
+			return int(int32(binary.BigEndian.Uint32(payload))), nil //nolint: gosec
+		case FrameError:
+			return -1, fmt.Errorf("agent failed to execute process: %s", payload)
+		default:
+			return -1, fmt.Errorf("%w: type %d", ErrUnexpectedFrame, t)
+		}
+		if err != nil {
+			return -1, err
+		}
+	}
+}
+
+func forwardStdin(fw *frameWriter, stdin io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			if fw.write(FrameStdin, buf[:n]) != nil {
+				return
+			}
+		}
+		if err != nil {
+			// Signal EOF to the process
+			_ = fw.write(FrameStdin, nil)
+			return
+		}
+	}
+}
+
+func writeOutput(w io.Writer, data []byte) error {
+	if w == nil {
+		return nil
+	}
+	_, err := w.Write(data)
+	return err
+}
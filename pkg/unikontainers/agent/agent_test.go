@@ -0,0 +1,154 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent reads the process and the stdin of the process from conn and
+// echoes the stdin back as stdout. It returns the received process.
+func fakeAgent(t *testing.T, conn net.Conn, exitCode int32) <-chan Process {
+	t.Helper()
+	received := make(chan Process, 1)
+	go func() {
+		defer conn.Close()
+		typ, payload, err := ReadFrame(conn)
+		if err != nil || typ != FrameProcess {
+			return
+		}
+		var proc Process
+		if json.Unmarshal(payload, &proc) != nil {
+			return
+		}
+		received <- proc
+		for {
+			typ, payload, err := ReadFrame(conn)
+			if err != nil || typ != FrameStdin {
+				return
+			}
+			if len(payload) == 0 {
+				break
+			}
+			_ = WriteFrame(conn, FrameStdout, payload)
+		}
+		_ = WriteFrame(conn, FrameStderr, []byte("done"))
+		status := make([]byte, 4)
+		binary.BigEndian.PutUint32(status, uint32(exitCode)) //nolint: gosec
+		_ = WriteFrame(conn, FrameExit, status)
+	}()
+
+	return received
+}
+
+func TestFrames(t *testing.T) {
+	t.Run("write and read frame", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, WriteFrame(&buf, FrameStdout, []byte("hello")))
+		require.NoError(t, WriteFrame(&buf, FrameStdin, nil))
+
+		typ, payload, err := ReadFrame(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, FrameStdout, typ)
+		assert.Equal(t, []byte("hello"), payload)
+
+		typ, payload, err = ReadFrame(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, FrameStdin, typ)
+		assert.Empty(t, payload)
+	})
+
+	t.Run("frame too large", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		err := WriteFrame(&buf, FrameStdout, make([]byte, maxFrameSize+1))
+		assert.ErrorIs(t, err, ErrFrameTooLarge)
+
+		hdr := []byte{byte(FrameStdout), 0xff, 0xff, 0xff, 0xff}
+		_, _, err = ReadFrame(bytes.NewReader(hdr))
+		assert.ErrorIs(t, err, ErrFrameTooLarge)
+	})
+}
+
+func TestExec(t *testing.T) {
+	t.Run("exec with stdio", func(t *testing.T) {
+		t.Parallel()
+		host, guest := net.Pipe()
+		defer host.Close()
+		received := fakeAgent(t, guest, 3)
+
+		proc := Process{
+			Args: []string{"/bin/cat"},
+			Env:  []string{"PATH=/bin"},
+			Cwd:  "/",
+			UID:  1000,
+			GID:  1000,
+		}
+		var stdout, stderr bytes.Buffer
+		code, err := Exec(host, proc, Stdio{
+			Stdin:  strings.NewReader("hello from the host"),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, code)
+		assert.Equal(t, proc, <-received)
+		assert.Equal(t, "hello from the host", stdout.String())
+		assert.Equal(t, "done", stderr.String())
+	})
+
+	t.Run("exec without stdin", func(t *testing.T) {
+		t.Parallel()
+		host, guest := net.Pipe()
+		defer host.Close()
+		fakeAgent(t, guest, 0)
+
+		code, err := Exec(host, Process{Args: []string{"/bin/true"}}, Stdio{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, code)
+	})
+
+	t.Run("agent error", func(t *testing.T) {
+		t.Parallel()
+		host, guest := net.Pipe()
+		defer host.Close()
+		go func() {
+			defer guest.Close()
+			_, _, _ = ReadFrame(guest)
+			_, _, _ = ReadFrame(guest)
+			_ = WriteFrame(guest, FrameError, []byte("no such file or directory"))
+		}()
+
+		_, err := Exec(host, Process{Args: []string{"/missing"}}, Stdio{})
+		assert.ErrorContains(t, err, "no such file or directory")
+	})
+
+	t.Run("no args", func(t *testing.T) {
+		t.Parallel()
+		host, _ := net.Pipe()
+		defer host.Close()
+		_, err := Exec(host, Process{}, Stdio{})
+		assert.Error(t, err)
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
 )
 
 var ErrMountpoint = errors.New("no FS is mounted in this mountpoint")
@@ -169,6 +170,29 @@ func handleExplicitBlockImage(blockImg string, mountPoint string) (types.BlockDe
 	}, nil
 }
 
+// handleExplicitBlockImages returns the block images of the
+// com.urunc.unikernel.blocks annotation, which reside in the container's
+// rootfs.
+func handleExplicitBlockImages(blocks string) ([]types.BlockDevParams, error) {
+	blockConfs, err := parseBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	blockImgs := make([]types.BlockDevParams, 0, len(blockConfs))
+	for _, b := range blockConfs {
+		blockImgs = append(blockImgs, types.BlockDevParams{
+			Source:     b.Source,
+			MountPoint: b.MountPoint,
+			FsType:     b.FsType,
+			ID:         b.ID,
+			ReadOnly:   b.ReadOnly,
+		})
+	}
+
+	return blockImgs, nil
+}
+
 func handleCntrRootfsAsBlock(rfs types.RootfsParams, unikernelType string, unikernelPath string, uruncJSONFilename string, initrdPath string, mounts []specs.Mount) (types.BlockDevParams, error) {
 	err := copyMountfiles(rfs.MountedPath, mounts)
 	if err != nil {
@@ -201,45 +225,183 @@ func handleCntrRootfsAsBlock(rfs types.RootfsParams, unikernelType string, unike
 	}, nil
 }
 
+var errNotBlockVolume = errors.New("volume is not backed by a block device")
+
+type volumeKind int
+
+const (
+	otherVolume volumeKind = iota
+	blockDevVolume
+	diskImageVolume
+)
+
+// parseDiskVolumes parses the (decoded) value of the
+// com.urunc.unikernel.diskVolumes annotation, which is a comma-separated
+// list with the destinations of the disk image volumes, each one optionally
+// followed by a colon and the format of the disk image. It returns the
+// declared format of each destination, which is empty if the format is not
+// declared.
+func parseDiskVolumes(diskVolumes string) (map[string]string, error) {
+	destinations := make(map[string]string)
+	for _, entry := range strings.Split(diskVolumes, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		dest, format, _ := strings.Cut(entry, ":")
+		switch format {
+		case "", diskFormatRaw, diskFormatQcow2, diskFormatVMDK:
+		default:
+			return nil, fmt.Errorf("invalid format %q of disk image volume %s in %s", format, dest, annotDiskVolumes)
+		}
+		destinations[filepath.Clean(dest)] = format
+	}
+
+	return destinations, nil
+}
+
+// getVolumeKind returns the kind of the given source of a bind mount,
+// which is either a block device node, a disk image or anything else.
+// A regular file is a disk image only if the container declares it as
+// such, since any file can get bind mounted in the container.
+func getVolumeKind(source string, diskImage bool) volumeKind {
+	info, err := os.Stat(source)
+	if err != nil {
+		return otherVolume
+	}
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0:
+		return blockDevVolume
+	case mode.IsRegular() && diskImage:
+		return diskImageVolume
+	default:
+		return otherVolume
+	}
+}
+
 // Search all the mount entries in the container's config and
-// find the ones that come from a block.
-func getBlockVolumes(monRootfs string, mounts []specs.Mount, ukernel types.Unikernel) ([]types.BlockDevParams, error) {
+// find the ones that come from a block. These are either bind mounts of
+// block devices and disk images, or bind mounts of directories where
+// a block device with a filesystem that the guest supports is mounted.
+// The disk images are the bind mounts of regular files whose destination
+// is in diskVolumes, which also holds their declared format.
+// Each one of them gets a stable ID based on the order of the mount
+// entries. If the guest can mount only maxVolumes volumes as block devices,
+// the rest of them are handled as any other mount entry. A negative
+// maxVolumes means that there is no limit. getBlockVolumes returns the block
+// devices along with the mount entries that do not come from a block.
+func getBlockVolumes(monRootfs string, mounts []specs.Mount, ukernel types.Unikernel, maxVolumes int, diskVolumes map[string]string) ([]types.BlockDevParams, []specs.Mount, error) {
 	blkImgs := []types.BlockDevParams{}
+	otherMounts := make([]specs.Mount, 0, len(mounts))
 	for i, m := range mounts {
 		// We check only bind mounts
 		if m.Type != "bind" {
+			otherMounts = append(otherMounts, m)
 			continue
 		}
-		// Get the information of the source path
-		// from /proc/self/mountinfo
-		mInfo, err := getMountInfo(m.Source)
-		if errors.Is(err, ErrMountpoint) {
-			// ErrMountpoint means we did not find any
-			// such mount and hence we can skip it.
+		format, diskImage := diskVolumes[filepath.Clean(m.Destination)]
+		if maxVolumes >= 0 && len(blkImgs) >= maxVolumes {
+			if isBlockVolume(m, ukernel, diskImage) {
+				uniklog.Warnf("the guest can not mount %s as a block device, handling it as a regular volume", m.Destination)
+			}
+			otherMounts = append(otherMounts, m)
+			continue
+		}
+		blk, err := blockVolume(monRootfs, m, ukernel, diskImage)
+		if errors.Is(err, errNotBlockVolume) {
+			otherMounts = append(otherMounts, m)
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		if ukernel.SupportsFS(mInfo.FsType) {
-			err = mount.Unmount(mInfo.MountPoint)
-			if err != nil {
-				return nil, err
-			}
-			err = setupDev(monRootfs, mInfo.Source)
-			if err != nil {
-				return nil, err
-			}
-			mInfo.ID = fmt.Sprintf("vol%d", i)
-			mInfo.MountPoint = m.Destination
-			blkImgs = append(blkImgs, mInfo)
+		blk.ID = fmt.Sprintf("vol%d", i)
+		blk.MountPoint = m.Destination
+		blk.ReadOnly = isReadOnlyMount(m.Options)
+		if diskImage {
+			blk.Format = format
 		}
+		blkImgs = append(blkImgs, blk)
 	}
 
-	return blkImgs, nil
+	return blkImgs, otherMounts, nil
 }
 
-func handleBlockBasedRootfs(rfs types.RootfsParams, ukernel types.Unikernel, unikernelType string, unikernelPath string, uruncJSONFilename string, initrdPath string, mounts []specs.Mount) ([]types.BlockDevParams, error) {
+// maxBlockVolumes returns the number of volumes that the guest can mount as
+// block devices, along with the given rootfs, or -1 if there is no limit.
+func maxBlockVolumes(ukernel types.Unikernel, params types.UnikernelParams, rfs types.RootfsParams) int {
+	limiter, ok := ukernel.(types.BlockMountLimiter)
+	if !ok {
+		return -1
+	}
+	maxVolumes := limiter.MaxBlockMounts(params.Monitor, params.Solo5Devs)
+	// The block-based rootfs is the first block device of the guest
+	if rfs.Type == "block" {
+		maxVolumes--
+	}
+
+	return max(maxVolumes, 0)
+}
+
+// isBlockVolume returns true if the given bind mount comes from a block,
+// which blockVolume would attach to the guest. Contrary to blockVolume,
+// it does not set up anything.
+func isBlockVolume(m specs.Mount, ukernel types.Unikernel, diskImage bool) bool {
+	if getVolumeKind(m.Source, diskImage) != otherVolume {
+		return true
+	}
+	mInfo, err := getMountInfo(m.Source)
+
+	return err == nil && ukernel.SupportsFS(mInfo.FsType)
+}
+
+// blockVolume sets up the block device of the given bind mount in the
+// monitor's rootfs. It returns errNotBlockVolume if the mount does not
+// come from a block.
+func blockVolume(monRootfs string, m specs.Mount, ukernel types.Unikernel, diskImage bool) (types.BlockDevParams, error) {
+	switch getVolumeKind(m.Source, diskImage) {
+	case blockDevVolume:
+		err := setupDev(monRootfs, m.Source)
+		if err != nil {
+			return types.BlockDevParams{}, err
+		}
+		return types.BlockDevParams{Source: m.Source}, nil
+	case diskImageVolume:
+		err := fileFromHost(monRootfs, m.Source, "", unix.MS_BIND|unix.MS_PRIVATE, false)
+		if err != nil {
+			return types.BlockDevParams{}, err
+		}
+		return types.BlockDevParams{Source: m.Source}, nil
+	}
+
+	// Get the information of the source path
+	// from /proc/self/mountinfo
+	mInfo, err := getMountInfo(m.Source)
+	if errors.Is(err, ErrMountpoint) {
+		// ErrMountpoint means we did not find any
+		// such mount and hence we can skip it.
+		return types.BlockDevParams{}, errNotBlockVolume
+	}
+	if err != nil {
+		return types.BlockDevParams{}, err
+	}
+	if !ukernel.SupportsFS(mInfo.FsType) {
+		return types.BlockDevParams{}, errNotBlockVolume
+	}
+	err = mount.Unmount(mInfo.MountPoint)
+	if err != nil {
+		return types.BlockDevParams{}, err
+	}
+	err = setupDev(monRootfs, mInfo.Source)
+	if err != nil {
+		return types.BlockDevParams{}, err
+	}
+
+	return mInfo, nil
+}
+
+func handleBlockBasedRootfs(rfs types.RootfsParams, unikernelType string, unikernelPath string, uruncJSONFilename string, initrdPath string, mounts []specs.Mount) ([]types.BlockDevParams, error) {
 	var blockArgs []types.BlockDevParams
 	var rootfsBlock types.BlockDevParams
 	var err error
@@ -254,12 +416,8 @@ func handleBlockBasedRootfs(rfs types.RootfsParams, ukernel types.Unikernel, uni
 		return nil, err
 	}
 	rootfsBlock.ID = "rootfs"
+	rootfsBlock.ReadOnly = rfs.ReadOnly
 	blockArgs = append(blockArgs, rootfsBlock)
-	blockFromMounts, err := getBlockVolumes(rfs.MonRootfs, mounts, ukernel)
-	if err != nil {
-		return nil, err
-	}
-	blockArgs = append(blockArgs, blockFromMounts...)
 
 	return blockArgs, nil
 }
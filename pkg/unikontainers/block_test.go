@@ -15,10 +15,15 @@
 package unikontainers
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/unikernels"
 )
 
 func TestGetBlockDevice(t *testing.T) {
@@ -37,3 +42,106 @@ func TestGetBlockDevice(t *testing.T) {
 	assert.Equal(t, tmpMnt.FsType, rootFs.FsType, "Expected filesystem type to be proc")
 	assert.Equal(t, tmpMnt.ID, rootFs.ID, "Expected ID to be empty")
 }
+
+func TestGetVolumeKind(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	for _, name := range []string{"data.img", "config.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte{}, 0o644))
+	}
+
+	// Only the declared disk image volumes are disk images
+	assert.Equal(t, diskImageVolume, getVolumeKind(filepath.Join(tmpDir, "data.img"), true))
+	assert.Equal(t, diskImageVolume, getVolumeKind(filepath.Join(tmpDir, "config.json"), true))
+	assert.Equal(t, otherVolume, getVolumeKind(filepath.Join(tmpDir, "data.img"), false))
+	assert.Equal(t, otherVolume, getVolumeKind(tmpDir, true))
+	assert.Equal(t, otherVolume, getVolumeKind(filepath.Join(tmpDir, "missing.img"), true))
+	assert.Equal(t, otherVolume, getVolumeKind("/dev/null", true))
+}
+
+func TestParseDiskVolumes(t *testing.T) {
+	t.Parallel()
+	diskVolumes, err := parseDiskVolumes("")
+	require.NoError(t, err)
+	assert.Empty(t, diskVolumes)
+
+	diskVolumes, err = parseDiskVolumes(" /data/, ,/var/lib/db:qcow2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/data": "", "/var/lib/db": diskFormatQcow2}, diskVolumes)
+
+	_, err = parseDiskVolumes("/data:vdi")
+	assert.ErrorContains(t, err, "vdi")
+}
+
+func TestGetBlockVolumesSkipsOtherMounts(t *testing.T) {
+	t.Parallel()
+	image := filepath.Join(t.TempDir(), "data.img")
+	require.NoError(t, os.WriteFile(image, []byte{}, 0o644))
+	mounts := []specs.Mount{
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+		{Destination: "/etc/hosts", Type: "bind", Source: filepath.Join(t.TempDir(), "hosts")},
+		// A disk image that the container does not declare
+		{Destination: "/data", Type: "bind", Source: image},
+	}
+
+	unikernel, err := unikernels.New(unikernels.LinuxUnikernel, "")
+	require.NoError(t, err)
+
+	blocks, otherMounts, err := getBlockVolumes(t.TempDir(), mounts, unikernel, -1, map[string]string{"/etc/hosts": ""})
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+	assert.Equal(t, mounts, otherMounts)
+}
+
+func TestGetBlockVolumesOverLimit(t *testing.T) {
+	t.Parallel()
+	image := filepath.Join(t.TempDir(), "data.img")
+	require.NoError(t, os.WriteFile(image, []byte{}, 0o644))
+	mounts := []specs.Mount{
+		{Destination: "/data", Type: "bind", Source: image},
+	}
+
+	unikernel, err := unikernels.New(unikernels.RumprunUnikernel, "")
+	require.NoError(t, err)
+
+	monRootfs := t.TempDir()
+	blocks, otherMounts, err := getBlockVolumes(monRootfs, mounts, unikernel, 0, map[string]string{"/data": ""})
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+	assert.Equal(t, mounts, otherMounts)
+	assert.NoFileExists(t, filepath.Join(monRootfs, image))
+}
+
+func TestMaxBlockVolumes(t *testing.T) {
+	t.Parallel()
+	linux, err := unikernels.New(unikernels.LinuxUnikernel, "")
+	require.NoError(t, err)
+	rumprun, err := unikernels.New(unikernels.RumprunUnikernel, "")
+	require.NoError(t, err)
+	mirage, err := unikernels.New(unikernels.MirageUnikernel, "")
+	require.NoError(t, err)
+
+	hvt := types.UnikernelParams{Monitor: "hvt"}
+	blockRootfs := types.RootfsParams{Type: "block"}
+	assert.Equal(t, -1, maxBlockVolumes(linux, hvt, blockRootfs))
+	assert.Equal(t, 1, maxBlockVolumes(rumprun, hvt, types.RootfsParams{}))
+	assert.Equal(t, 0, maxBlockVolumes(rumprun, hvt, blockRootfs))
+
+	// MirageOS mounts the block devices of its manifest
+	assert.Equal(t, 1, maxBlockVolumes(mirage, hvt, types.RootfsParams{}))
+	withManifest := types.UnikernelParams{
+		Monitor:   "spt",
+		Solo5Devs: types.Solo5Devices{Block: []string{"storage", "data", "logs"}},
+	}
+	assert.Equal(t, 3, maxBlockVolumes(mirage, withManifest, types.RootfsParams{}))
+	assert.Equal(t, 0, maxBlockVolumes(mirage, types.UnikernelParams{Monitor: "qemu"}, types.RootfsParams{}))
+}
+
+func TestIsReadOnlyMount(t *testing.T) {
+	t.Parallel()
+	assert.False(t, isReadOnlyMount(nil))
+	assert.False(t, isReadOnlyMount([]string{"rbind", "rprivate"}))
+	assert.True(t, isReadOnlyMount([]string{"rbind", "ro"}))
+	assert.False(t, isReadOnlyMount([]string{"ro", "rw"}))
+	assert.True(t, isReadOnlyMount([]string{"rw", "nosuid", "ro"}))
+}
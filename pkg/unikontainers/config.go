@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
@@ -44,7 +45,12 @@ const (
 	annotInitrd        = "com.urunc.unikernel.initrd"
 	annotBlock         = "com.urunc.unikernel.block"
 	annotBlockMntPoint = "com.urunc.unikernel.blkMntPoint"
+	annotBlocks        = "com.urunc.unikernel.blocks"
 	annotMountRootfs   = "com.urunc.unikernel.mountRootfs"
+	annotDNS           = "com.urunc.unikernel.dns"
+	annotAgent         = "com.urunc.unikernel.agent"
+	annotUrunitFeats   = "com.urunc.unikernel.urunitFeatures"
+	annotDiskVolumes   = "com.urunc.unikernel.diskVolumes"
 )
 
 // A UnikernelConfig struct holds the info provided by bima image on how to execute our unikernel
@@ -57,10 +63,78 @@ type UnikernelConfig struct {
 	Initrd           string `json:"com.urunc.unikernel.initrd,omitempty"`
 	Block            string `json:"com.urunc.unikernel.block,omitempty"`
 	BlkMntPoint      string `json:"com.urunc.unikernel.blkMntPoint,omitempty"`
+	Blocks           string `json:"com.urunc.unikernel.blocks,omitempty"`
 	MountRootfs      string `json:"com.urunc.unikernel.mountRootfs"`
+	DNS              string `json:"com.urunc.unikernel.dns,omitempty"`
+	Agent            string `json:"com.urunc.unikernel.agent,omitempty"`
+	UrunitFeatures   string `json:"com.urunc.unikernel.urunitFeatures,omitempty"`
+	DiskVolumes      string `json:"com.urunc.unikernel.diskVolumes,omitempty"`
 }
 
-// validate checks if the mandatory configuration fields are present.
+// A BlockConfig describes a block image inside the container's rootfs,
+// which gets attached to the unikernel. The com.urunc.unikernel.blocks
+// annotation holds a JSON list of them.
+type BlockConfig struct {
+	Source     string `json:"source"`
+	MountPoint string `json:"mountPoint"`
+	FsType     string `json:"fsType,omitempty"`
+	ReadOnly   bool   `json:"readOnly,omitempty"`
+	ID         string `json:"id,omitempty"`
+}
+
+// The IDs of block images end up in the command line of the monitors and
+// in the fstab of some unikernels.
+var blockIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// The IDs of the block based volumes of the container
+var volumeIDRegexp = regexp.MustCompile(`^vol[0-9]+$`)
+
+// parseBlocks parses the (decoded) value of the com.urunc.unikernel.blocks
+// annotation and checks that each block image has a source, an absolute
+// mount point other than "/" and a unique ID. Block images without an ID
+// get one based on their position in the list.
+func parseBlocks(blocks string) ([]BlockConfig, error) {
+	if blocks == "" {
+		return nil, nil
+	}
+
+	var blockConfs []BlockConfig
+	if err := json.Unmarshal([]byte(blocks), &blockConfs); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", annotBlocks, err)
+	}
+
+	ids := make(map[string]bool)
+	for i := range blockConfs {
+		b := &blockConfs[i]
+		if b.Source == "" {
+			return nil, fmt.Errorf("block %d of %s has no source", i, annotBlocks)
+		}
+		if !filepath.IsAbs(b.MountPoint) || filepath.Clean(b.MountPoint) == "/" {
+			// The block image for the rootfs is set with com.urunc.unikernel.block
+			return nil, fmt.Errorf("block %d of %s has invalid mount point %q", i, annotBlocks, b.MountPoint)
+		}
+		if b.ID == "" {
+			b.ID = fmt.Sprintf("blk%d", i)
+		}
+		if !blockIDRegexp.MatchString(b.ID) {
+			return nil, fmt.Errorf("block %d of %s has invalid ID %q", i, annotBlocks, b.ID)
+		}
+		// The IDs of the rootfs, the block image of com.urunc.unikernel.block
+		// and the volumes are reserved.
+		if b.ID == "rootfs" || b.ID == "annot_vol" || volumeIDRegexp.MatchString(b.ID) {
+			return nil, fmt.Errorf("block %d of %s uses reserved ID %q", i, annotBlocks, b.ID)
+		}
+		if ids[b.ID] {
+			return nil, fmt.Errorf("block %d of %s has duplicate ID %q", i, annotBlocks, b.ID)
+		}
+		ids[b.ID] = true
+	}
+
+	return blockConfs, nil
+}
+
+// validate checks if the mandatory configuration fields are present and
+// if the (decoded) block images are valid.
 func (c *UnikernelConfig) validate() error {
 	if c.UnikernelType == "" {
 		return fmt.Errorf("unikernel configuration is missing mandatory field: %s", annotType)
@@ -71,24 +145,28 @@ func (c *UnikernelConfig) validate() error {
 	if c.UnikernelBinary == "" {
 		return fmt.Errorf("unikernel configuration is missing mandatory field: %s", annotBinary)
 	}
+	if _, err := parseBlocks(c.Blocks); err != nil {
+		return err
+	}
 	return nil
 }
 
-// GetUnikernelConfig tries to get the Unikernel config from the bundle annotations.
-// If that fails, it gets the Unikernel config from the urunc.json file inside the rootfs.
+// GetUnikernelConfig gets the Unikernel config from the bundle annotations.
+// If the annotations do not describe the unikernel, it gets the Unikernel
+// config from the urunc.json file inside the rootfs.
 // FIXME: custom annotations are unreachable, we need to investigate why to skip adding the urunc.json file
 // For more details, see: https://github.com/urunc-dev/urunc/issues/12
-// GetUnikernelConfig tries to get a valid Unikernel config from the bundle annotations.
-// If the annotations do not provide a valid config, it falls back to the urunc.json file.
 func GetUnikernelConfig(bundleDir string, spec *specs.Spec) (*UnikernelConfig, error) {
 
 	conf := getConfigFromSpec(spec)
 
-	err := conf.validate()
-	if err == nil {
+	if err := conf.decode(); err != nil {
+		return nil, fmt.Errorf("invalid unikernel config from spec annotations: %w", err)
+	}
 
-		if err := conf.decode(); err != nil {
-			return nil, err
+	if conf.describesUnikernel() {
+		if err := conf.validate(); err != nil {
+			return nil, fmt.Errorf("invalid unikernel config from spec annotations: %w", err)
 		}
 		return conf, nil
 	}
@@ -106,16 +184,40 @@ func GetUnikernelConfig(bundleDir string, spec *specs.Spec) (*UnikernelConfig, e
 		return nil, fmt.Errorf("config not found in spec annotations or in %s: %w", uruncJSONFilename, err)
 	}
 
+	if err := jsonConf.decode(); err != nil {
+		return nil, err
+	}
+
 	if err := jsonConf.validate(); err != nil {
 		return nil, fmt.Errorf("invalid unikernel config from %s: %w", uruncJSONFilename, err)
 	}
 
-	if err := jsonConf.decode(); err != nil {
-		return nil, err
+	// The annotations of the container itself apply on top of the
+	// config of the image.
+	if conf.DNS != "" {
+		jsonConf.DNS = conf.DNS
+	}
+	if conf.Agent != "" {
+		jsonConf.Agent = conf.Agent
+	}
+	if conf.DiskVolumes != "" {
+		jsonConf.DiskVolumes = conf.DiskVolumes
 	}
 	return jsonConf, nil
 }
 
+// describesUnikernel returns true if any of the fields which describe the
+// unikernel of the image is set. The rest of the fields (i.e. the DNS
+// servers, the guest agent and the disk image volumes) are usually set on
+// the container.
+func (c *UnikernelConfig) describesUnikernel() bool {
+	image := *c
+	image.DNS = ""
+	image.Agent = ""
+	image.DiskVolumes = ""
+	return image != UnikernelConfig{}
+}
+
 // getConfigFromSpec retrieves the urunc specific annotations from the spec and populates the Unikernel config.
 func getConfigFromSpec(spec *specs.Spec) *UnikernelConfig {
 	unikernelType := spec.Annotations[annotType]
@@ -126,7 +228,12 @@ func getConfigFromSpec(spec *specs.Spec) *UnikernelConfig {
 	initrd := spec.Annotations[annotInitrd]
 	block := spec.Annotations[annotBlock]
 	blkMntPoint := spec.Annotations[annotBlockMntPoint]
+	blocks := spec.Annotations[annotBlocks]
 	MountRootfs := spec.Annotations[annotMountRootfs]
+	dns := spec.Annotations[annotDNS]
+	agent := spec.Annotations[annotAgent]
+	urunitFeatures := spec.Annotations[annotUrunitFeats]
+	diskVolumes := spec.Annotations[annotDiskVolumes]
 	uniklog.WithFields(logrus.Fields{
 		"unikernelType":    tryDecode(unikernelType),
 		"unikernelVersion": tryDecode(unikernelVersion),
@@ -136,7 +243,12 @@ func getConfigFromSpec(spec *specs.Spec) *UnikernelConfig {
 		"initrd":           tryDecode(initrd),
 		"block":            tryDecode(block),
 		"blkMntPoint":      tryDecode(blkMntPoint),
+		"blocks":           tryDecode(blocks),
 		"mountRootfs":      tryDecode(MountRootfs),
+		"dns":              tryDecode(dns),
+		"agent":            tryDecode(agent),
+		"urunitFeatures":   tryDecode(urunitFeatures),
+		"diskVolumes":      tryDecode(diskVolumes),
 	}).WithField("source", "spec").Debug("urunc annotations")
 
 	return &UnikernelConfig{
@@ -148,7 +260,12 @@ func getConfigFromSpec(spec *specs.Spec) *UnikernelConfig {
 		Initrd:           initrd,
 		Block:            block,
 		BlkMntPoint:      blkMntPoint,
+		Blocks:           blocks,
 		MountRootfs:      MountRootfs,
+		DNS:              dns,
+		Agent:            agent,
+		UrunitFeatures:   urunitFeatures,
+		DiskVolumes:      diskVolumes,
 	}
 }
 
@@ -187,7 +304,12 @@ func getConfigFromJSON(jsonFilePath string) (*UnikernelConfig, error) {
 		"initrd":           tryDecode(conf.Initrd),
 		"block":            tryDecode(conf.Block),
 		"blkMntPoint":      tryDecode(conf.BlkMntPoint),
+		"blocks":           tryDecode(conf.Blocks),
 		"mountRootfs":      tryDecode(conf.MountRootfs),
+		"dns":              tryDecode(conf.DNS),
+		"agent":            tryDecode(conf.Agent),
+		"urunitFeatures":   tryDecode(conf.UrunitFeatures),
+		"diskVolumes":      tryDecode(conf.DiskVolumes),
 	}).WithField("source", uruncJSONFilename).Debug("urunc annotations")
 
 	return &conf, nil
@@ -252,15 +374,78 @@ func (c *UnikernelConfig) decode() error {
 	}
 	c.BlkMntPoint = string(decoded)
 
+	decoded, err = base64.StdEncoding.DecodeString(c.Blocks)
+	if err != nil {
+		return fmt.Errorf("failed to decode Blocks: %v", err)
+	}
+	c.Blocks = string(decoded)
+
 	decoded, err = base64.StdEncoding.DecodeString(c.MountRootfs)
 	if err != nil {
 		return fmt.Errorf("failed to decode mountRootfs: %v", err)
 	}
 	c.MountRootfs = string(decoded)
 
+	decoded, err = base64.StdEncoding.DecodeString(c.DNS)
+	if err != nil {
+		return fmt.Errorf("failed to decode DNS: %v", err)
+	}
+	c.DNS = string(decoded)
+
+	decoded, err = base64.StdEncoding.DecodeString(c.Agent)
+	if err != nil {
+		return fmt.Errorf("failed to decode Agent: %v", err)
+	}
+	c.Agent = string(decoded)
+
+	decoded, err = base64.StdEncoding.DecodeString(c.UrunitFeatures)
+	if err != nil {
+		return fmt.Errorf("failed to decode UrunitFeatures: %v", err)
+	}
+	c.UrunitFeatures = string(decoded)
+
+	decoded, err = base64.StdEncoding.DecodeString(c.DiskVolumes)
+	if err != nil {
+		return fmt.Errorf("failed to decode DiskVolumes: %v", err)
+	}
+	c.DiskVolumes = string(decoded)
+
 	return nil
 }
 
+// encode base64 encodes the values of the Unikernel config. It is the
+// reverse operation of decode.
+func (c *UnikernelConfig) encode() {
+	c.UnikernelCmd = base64.StdEncoding.EncodeToString([]byte(c.UnikernelCmd))
+	c.Hypervisor = base64.StdEncoding.EncodeToString([]byte(c.Hypervisor))
+	c.UnikernelType = base64.StdEncoding.EncodeToString([]byte(c.UnikernelType))
+	c.UnikernelVersion = base64.StdEncoding.EncodeToString([]byte(c.UnikernelVersion))
+	c.UnikernelBinary = base64.StdEncoding.EncodeToString([]byte(c.UnikernelBinary))
+	c.Initrd = base64.StdEncoding.EncodeToString([]byte(c.Initrd))
+	c.Block = base64.StdEncoding.EncodeToString([]byte(c.Block))
+	c.BlkMntPoint = base64.StdEncoding.EncodeToString([]byte(c.BlkMntPoint))
+	c.Blocks = base64.StdEncoding.EncodeToString([]byte(c.Blocks))
+	c.MountRootfs = base64.StdEncoding.EncodeToString([]byte(c.MountRootfs))
+	c.DNS = base64.StdEncoding.EncodeToString([]byte(c.DNS))
+	c.Agent = base64.StdEncoding.EncodeToString([]byte(c.Agent))
+	c.UrunitFeatures = base64.StdEncoding.EncodeToString([]byte(c.UrunitFeatures))
+	c.DiskVolumes = base64.StdEncoding.EncodeToString([]byte(c.DiskVolumes))
+}
+
+// Annotations validates the (decoded) Unikernel config and returns the
+// base64 encoded urunc annotations that describe it, in the same form that
+// getConfigFromSpec expects to find them in a spec.
+func (c *UnikernelConfig) Annotations() (map[string]string, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	encoded := *c
+	encoded.encode()
+
+	return encoded.Map(), nil
+}
+
 // Map returns a map containing the Unikernel config data
 func (c *UnikernelConfig) Map() map[string]string {
 	myMap := make(map[string]string)
@@ -288,9 +473,24 @@ func (c *UnikernelConfig) Map() map[string]string {
 	if c.BlkMntPoint != "" {
 		myMap[annotBlockMntPoint] = c.BlkMntPoint
 	}
+	if c.Blocks != "" {
+		myMap[annotBlocks] = c.Blocks
+	}
 	if c.MountRootfs != "" {
 		myMap[annotMountRootfs] = c.MountRootfs
 	}
+	if c.DNS != "" {
+		myMap[annotDNS] = c.DNS
+	}
+	if c.Agent != "" {
+		myMap[annotAgent] = c.Agent
+	}
+	if c.UrunitFeatures != "" {
+		myMap[annotUrunitFeats] = c.UrunitFeatures
+	}
+	if c.DiskVolumes != "" {
+		myMap[annotDiskVolumes] = c.DiskVolumes
+	}
 
 	return myMap
 }
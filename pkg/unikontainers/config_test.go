@@ -38,6 +38,7 @@ func TestGetConfigFromSpec(t *testing.T) {
 				annotBlock:         "block1",
 				annotBlockMntPoint: "point1",
 				annotMountRootfs:   "true",
+				annotDNS:           "dns1",
 			},
 		}
 
@@ -50,6 +51,7 @@ func TestGetConfigFromSpec(t *testing.T) {
 			Block:           "block1",
 			BlkMntPoint:     "point1",
 			MountRootfs:     "true",
+			DNS:             "dns1",
 		}
 
 		config := getConfigFromSpec(spec)
@@ -106,6 +108,7 @@ func TestGetConfigFromJSON(t *testing.T) {
 			Block:           "block1",
 			BlkMntPoint:     "point1",
 			MountRootfs:     "true",
+			DNS:             "dns1",
 		}
 		configData, err := json.Marshal(expectedConfig)
 		assert.NoError(t, err)
@@ -239,6 +242,7 @@ func TestMap(t *testing.T) {
 			Block:           "block_value",
 			BlkMntPoint:     "point_value",
 			MountRootfs:     "false",
+			DNS:             "dns_value",
 		}
 		expectedMap := map[string]string{
 			annotCmdLine:       "cmd_value",
@@ -249,6 +253,7 @@ func TestMap(t *testing.T) {
 			annotBlock:         "block_value",
 			annotBlockMntPoint: "point_value",
 			annotMountRootfs:   "false",
+			annotDNS:           "dns_value",
 		}
 		resultMap := config.Map()
 		assert.Equal(t, expectedMap, resultMap)
@@ -300,3 +305,180 @@ func TestMap(t *testing.T) {
 		assert.Equal(t, expectedMap, resultMap)
 	})
 }
+
+func TestAnnotations(t *testing.T) {
+	t.Run("annotations round trip", func(t *testing.T) {
+		t.Parallel()
+		config := &UnikernelConfig{
+			UnikernelBinary: "/unikernel/app",
+			UnikernelType:   "unikraft",
+			UnikernelCmd:    "app -v",
+			Hypervisor:      "qemu",
+			Initrd:          "/unikernel/initrd",
+			MountRootfs:     "true",
+			DNS:             "10.96.0.10,1.1.1.1",
+		}
+		annotations, err := config.Annotations()
+		assert.NoError(t, err)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("qemu")), annotations[annotHypervisor])
+		assert.NotContains(t, annotations, annotBlock, "Expected empty fields to be omitted")
+
+		spec := &specs.Spec{Annotations: annotations}
+		decoded := getConfigFromSpec(spec)
+		err = decoded.decode()
+		assert.NoError(t, err)
+		assert.Equal(t, config, decoded, "Expected decoded config to match the original")
+	})
+
+	t.Run("annotations invalid config", func(t *testing.T) {
+		t.Parallel()
+		config := &UnikernelConfig{
+			UnikernelType: "unikraft",
+		}
+		_, err := config.Annotations()
+		assert.ErrorContains(t, err, annotHypervisor)
+	})
+}
+
+func TestParseBlocks(t *testing.T) {
+	t.Run("parse blocks success", func(t *testing.T) {
+		t.Parallel()
+		blocks, err := parseBlocks(`[
+			{"source": "/disks/data.img", "mountPoint": "/data", "fsType": "ext4", "readOnly": true, "id": "data"},
+			{"source": "/disks/logs.img", "mountPoint": "/var/log"}
+		]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []BlockConfig{
+			{Source: "/disks/data.img", MountPoint: "/data", FsType: "ext4", ReadOnly: true, ID: "data"},
+			{Source: "/disks/logs.img", MountPoint: "/var/log", ID: "blk1"},
+		}, blocks)
+	})
+
+	t.Run("parse empty blocks", func(t *testing.T) {
+		t.Parallel()
+		blocks, err := parseBlocks("")
+		assert.NoError(t, err)
+		assert.Empty(t, blocks)
+	})
+
+	t.Run("parse invalid blocks", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]string{
+			"invalid JSON":        `{"source": "/a.img"}`,
+			"missing source":      `[{"mountPoint": "/data"}]`,
+			"missing mount point": `[{"source": "/a.img"}]`,
+			"relative mount":      `[{"source": "/a.img", "mountPoint": "data"}]`,
+			"root mount":          `[{"source": "/a.img", "mountPoint": "/"}]`,
+			"invalid ID":          `[{"source": "/a.img", "mountPoint": "/data", "id": "a:b"}]`,
+			"reserved ID":         `[{"source": "/a.img", "mountPoint": "/data", "id": "rootfs"}]`,
+			"volume ID":           `[{"source": "/a.img", "mountPoint": "/data", "id": "vol0"}]`,
+			"duplicate ID": `[{"source": "/a.img", "mountPoint": "/a", "id": "blk1"},
+				{"source": "/b.img", "mountPoint": "/b"}]`,
+		}
+		for name, blocks := range tests {
+			_, err := parseBlocks(blocks)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestGetUnikernelConfigBlocks(t *testing.T) {
+	blocks := `[{"source": "/disks/data.img", "mountPoint": "/data", "readOnly": true}]`
+	config := &UnikernelConfig{
+		UnikernelBinary: "/unikernel/app",
+		UnikernelType:   "unikraft",
+		Hypervisor:      "qemu",
+		Blocks:          blocks,
+	}
+
+	t.Run("blocks from spec annotations", func(t *testing.T) {
+		t.Parallel()
+		annotations, err := config.Annotations()
+		assert.NoError(t, err)
+		spec := &specs.Spec{Annotations: annotations, Root: &specs.Root{Path: rootfsDirName}}
+
+		conf, err := GetUnikernelConfig(t.TempDir(), spec)
+		assert.NoError(t, err)
+		assert.Equal(t, blocks, conf.Blocks)
+	})
+
+	t.Run("blocks from urunc.json", func(t *testing.T) {
+		t.Parallel()
+		bundleDir := t.TempDir()
+		rootfsDir := filepath.Join(bundleDir, rootfsDirName)
+		assert.NoError(t, os.Mkdir(rootfsDir, 0755))
+		encoded := *config
+		encoded.encode()
+		configData, err := json.Marshal(encoded)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(rootfsDir, uruncJSONFilename), configData, 0600))
+
+		spec := &specs.Spec{Annotations: map[string]string{}, Root: &specs.Root{Path: rootfsDirName}}
+		conf, err := GetUnikernelConfig(bundleDir, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, blocks, conf.Blocks)
+	})
+
+	t.Run("invalid blocks", func(t *testing.T) {
+		t.Parallel()
+		invalid := *config
+		invalid.Blocks = `[{"source": "/disks/data.img", "mountPoint": "/"}]`
+		_, err := invalid.Annotations()
+		assert.ErrorContains(t, err, annotBlocks)
+	})
+}
+
+func TestGetUnikernelConfig(t *testing.T) {
+	config := &UnikernelConfig{
+		UnikernelBinary: "/unikernel/app",
+		UnikernelType:   "unikraft",
+		Hypervisor:      "qemu",
+	}
+	// newBundle returns a bundle with a valid urunc.json in its rootfs
+	newBundle := func(t *testing.T) string {
+		bundleDir := t.TempDir()
+		rootfsDir := filepath.Join(bundleDir, rootfsDirName)
+		assert.NoError(t, os.Mkdir(rootfsDir, 0755))
+		encoded := *config
+		encoded.encode()
+		configData, err := json.Marshal(encoded)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(rootfsDir, uruncJSONFilename), configData, 0600))
+		return bundleDir
+	}
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	t.Run("partial annotations do not fall back to urunc.json", func(t *testing.T) {
+		t.Parallel()
+		spec := &specs.Spec{
+			Annotations: map[string]string{annotType: encode("unikraft")},
+			Root:        &specs.Root{Path: rootfsDirName},
+		}
+		_, err := GetUnikernelConfig(newBundle(t), spec)
+		assert.ErrorContains(t, err, annotHypervisor)
+	})
+
+	t.Run("undecodable annotations do not fall back to urunc.json", func(t *testing.T) {
+		t.Parallel()
+		annotations, err := config.Annotations()
+		assert.NoError(t, err)
+		annotations[annotCmdLine] = "not base64!"
+		spec := &specs.Spec{Annotations: annotations, Root: &specs.Root{Path: rootfsDirName}}
+		_, err = GetUnikernelConfig(newBundle(t), spec)
+		assert.ErrorContains(t, err, "UnikernelCmd")
+	})
+
+	t.Run("container annotations apply on top of urunc.json", func(t *testing.T) {
+		t.Parallel()
+		spec := &specs.Spec{
+			Annotations: map[string]string{annotDNS: encode("1.1.1.1")},
+			Root:        &specs.Root{Path: rootfsDirName},
+		}
+		conf, err := GetUnikernelConfig(newBundle(t), spec)
+		assert.NoError(t, err)
+		assert.Equal(t, "qemu", conf.Hypervisor)
+		assert.Equal(t, "1.1.1.1", conf.DNS)
+	})
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const (
+	diskFormatRaw   = "raw"
+	diskFormatQcow2 = "qcow2"
+	diskFormatVMDK  = "vmdk"
+	// The directory inside the monitor's rootfs, where urunc places the
+	// raw images that it converts for the monitors.
+	convertedDisksDir = "/.urunc/disks"
+	// The maximum size of the embedded descriptor of a VMDK image that
+	// urunc reads
+	maxVMDKDescriptorSize = 1 << 20
+)
+
+var (
+	qcow2Magic          = []byte{'Q', 'F', 'I', 0xfb}
+	vmdkSparseMagic     = []byte("KDMV")
+	vmdkDescriptorMagic = []byte("# Disk DescriptorFile")
+)
+
+// The incompatible feature bit of qcow2 for images with an external data file
+const qcow2ExternalDataFile = 1 << 2
+
+// probeDiskFormat returns the format of the given disk image, based on the
+// magic of its header. Any image without a known magic is a raw image.
+// Images which refer to other files of the host (e.g. qcow2 images with a
+// backing file) are not supported, since the monitor would open these
+// files too.
+func probeDiskFormat(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the header of %s: %w", path, err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, qcow2Magic):
+		return diskFormatQcow2, checkQcow2Header(header)
+	case bytes.HasPrefix(header, vmdkSparseMagic):
+		return diskFormatVMDK, checkVMDKHeader(file, header)
+	case bytes.HasPrefix(header, vmdkDescriptorMagic):
+		return "", fmt.Errorf("vmdk images with a separate descriptor are not supported")
+	default:
+		return diskFormatRaw, nil
+	}
+}
+
+// checkQcow2Header checks that a qcow2 image does not have a backing file
+// or an external data file.
+func checkQcow2Header(header []byte) error {
+	// The header of version 2 ends after the snapshots offset and the
+	// header of version 3 after the header length.
+	if len(header) < 72 {
+		return fmt.Errorf("truncated qcow2 header")
+	}
+	if binary.BigEndian.Uint64(header[8:16]) != 0 {
+		return fmt.Errorf("qcow2 images with a backing file are not supported")
+	}
+	version := binary.BigEndian.Uint32(header[4:8])
+	if version >= 3 {
+		if len(header) < 104 {
+			return fmt.Errorf("truncated qcow2 header")
+		}
+		if binary.BigEndian.Uint64(header[72:80])&qcow2ExternalDataFile != 0 {
+			return fmt.Errorf("qcow2 images with an external data file are not supported")
+		}
+	}
+
+	return nil
+}
+
+// checkVMDKHeader checks that the embedded descriptor of a sparse VMDK image
+// does not refer to a parent image.
+func checkVMDKHeader(file *os.File, header []byte) error {
+	if len(header) < 44 {
+		return fmt.Errorf("truncated vmdk header")
+	}
+	// The offset and the size of the descriptor are in sectors
+	offset := binary.LittleEndian.Uint64(header[28:36]) * 512
+	size := binary.LittleEndian.Uint64(header[36:44]) * 512
+	if size == 0 {
+		return nil
+	}
+	if size > maxVMDKDescriptorSize {
+		return fmt.Errorf("invalid vmdk descriptor size %d", size)
+	}
+
+	descriptor := make([]byte, size)
+	n, err := file.ReadAt(descriptor, int64(offset)) //nolint: gosec
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read the vmdk descriptor: %w", err)
+	}
+	if bytes.Contains(descriptor[:n], []byte("parentFileNameHint")) {
+		return fmt.Errorf("vmdk images with a parent image are not supported")
+	}
+
+	return nil
+}
+
+// setupDiskFormats sets the format of the disk images among the block
+// devices of the guest. The format of a block is either the declared one
+// (i.e. its Format) or the probed one. Since the guest controls the header
+// of the writable volumes, their format is never probed and they are raw
+// images, unless the container declares their format. The disk images in a
+// format that the monitor does not support get converted to raw images
+// inside the monitor's rootfs. Since the writes of the guest do not reach
+// the original image, only read-only volumes and the disk images of the
+// container's image, which do not outlive the container, get converted.
+func setupDiskFormats(monRootfs string, blocks []types.BlockDevParams, vmm types.VMM, qemuImg types.ExtraBinConfig) error {
+	for i := range blocks {
+		blk := &blocks[i]
+		declared := blk.Format
+		blk.Format = diskFormatRaw
+		hostPath := filepath.Join(monRootfs, blk.Source)
+		info, err := os.Stat(hostPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat block %s: %w", blk.Source, err)
+		}
+		// The block devices of the host are always raw
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		writable := volumeIDRegexp.MatchString(blk.ID) && !blk.ReadOnly
+		format := declared
+		if format == "" && writable {
+			format = diskFormatRaw
+		}
+		// The header of an image with a declared format other than raw
+		// still gets checked, since it might refer to other files.
+		if format != diskFormatRaw {
+			probed, err := probeDiskFormat(hostPath)
+			if err != nil {
+				return fmt.Errorf("invalid disk image %s: %w", blk.Source, err)
+			}
+			if format != "" && probed != format {
+				return fmt.Errorf("disk image %s is not a %s image", blk.Source, format)
+			}
+			format = probed
+		}
+		if vmm.SupportsDiskFormat(format) {
+			blk.Format = format
+			continue
+		}
+
+		if writable {
+			return fmt.Errorf("the monitor does not support %s images and the writable volume %s can not get converted", format, blk.Source)
+		}
+		rawPath := filepath.Join(convertedDisksDir, blk.ID+".raw")
+		err = convertDiskImage(qemuImg, hostPath, format, filepath.Join(monRootfs, rawPath))
+		if err != nil {
+			return err
+		}
+		blk.Source = rawPath
+	}
+
+	return nil
+}
+
+// convertDiskImage converts a disk image of the given format to a raw image
+// with qemu-img.
+func convertDiskImage(qemuImg types.ExtraBinConfig, src string, format string, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
+	}
+
+	args := []string{"convert", "-f", format, "-O", diskFormatRaw}
+	if qemuImg.Options != "" {
+		args = append(args, strings.Fields(qemuImg.Options)...)
+	}
+	args = append(args, src, dst)
+
+	// #nosec G204 -- qemu-img path and options come from the urunc configuration, which is considered trusted
+	cmd := exec.Command(qemuImg.Path, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to convert %s to a raw image: %s: %w", src, strings.TrimSpace(string(output)), err)
+	}
+	uniklog.WithField("image", src).Debugf("converted %s image to raw", format)
+
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func qcow2Header(version uint32, backingOffset uint64, incompatible uint64) []byte {
+	header := make([]byte, 512)
+	copy(header, qcow2Magic)
+	binary.BigEndian.PutUint32(header[4:], version)
+	binary.BigEndian.PutUint64(header[8:], backingOffset)
+	binary.BigEndian.PutUint64(header[72:], incompatible)
+	return header
+}
+
+func vmdkImage(descriptor string) []byte {
+	image := make([]byte, 1024)
+	copy(image, vmdkSparseMagic)
+	if descriptor != "" {
+		// The descriptor starts at the second sector and spans one sector
+		binary.LittleEndian.PutUint64(image[28:], 1)
+		binary.LittleEndian.PutUint64(image[36:], 1)
+		copy(image[512:], descriptor)
+	}
+	return image
+}
+
+func TestProbeDiskFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		data   []byte
+		format string
+		valid  bool
+	}{
+		{name: "raw", data: []byte("an ext4 filesystem"), format: diskFormatRaw, valid: true},
+		{name: "empty", data: []byte{}, format: diskFormatRaw, valid: true},
+		{name: "qcow2 v2", data: qcow2Header(2, 0, 0), format: diskFormatQcow2, valid: true},
+		{name: "qcow2 v3", data: qcow2Header(3, 0, 0), format: diskFormatQcow2, valid: true},
+		{name: "qcow2 with backing file", data: qcow2Header(3, 0x200, 0)},
+		{name: "qcow2 with external data file", data: qcow2Header(3, 0, qcow2ExternalDataFile)},
+		{name: "truncated qcow2", data: qcow2Magic},
+		{name: "sparse vmdk", data: vmdkImage(`createType="monolithicSparse"`), format: diskFormatVMDK, valid: true},
+		{name: "vmdk without descriptor", data: vmdkImage(""), format: diskFormatVMDK, valid: true},
+		{name: "vmdk with parent", data: vmdkImage(`parentFileNameHint="/etc/shadow"`)},
+		{name: "vmdk descriptor", data: []byte("# Disk DescriptorFile\nversion=1\n")},
+	}
+
+	tmpDir := t.TempDir()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(tmpDir, tc.name)
+			require.NoError(t, os.WriteFile(path, tc.data, 0o644))
+
+			format, err := probeDiskFormat(path)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.format, format)
+		})
+	}
+}
+
+// fakeQemuImg returns a qemu-img that copies the source image to the
+// destination, instead of converting it.
+func fakeQemuImg(t *testing.T) types.ExtraBinConfig {
+	path := filepath.Join(t.TempDir(), "qemu-img")
+	script := "#!/bin/sh\nfor arg; do src=$dst; dst=$arg; done\ncp \"$src\" \"$dst\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755)) //nolint: gosec
+	return types.ExtraBinConfig{Path: path}
+}
+
+func TestSetupDiskFormats(t *testing.T) {
+	t.Parallel()
+	qemuImg := fakeQemuImg(t)
+	newVMM := func(vmmType hypervisors.VmmType) types.VMM {
+		monitors := map[string]types.MonitorConfig{string(vmmType): {BinaryPath: "/bin/true"}}
+		vmm, err := hypervisors.NewVMM(vmmType, monitors)
+		require.NoError(t, err)
+		return vmm
+	}
+	tests := []struct {
+		name     string
+		vmm      hypervisors.VmmType
+		block    types.BlockDevParams
+		data     []byte
+		source   string
+		format   string
+		errorMsg string
+	}{
+		{
+			name:   "raw image",
+			vmm:    hypervisors.HvtVmm,
+			block:  types.BlockDevParams{Source: "/disk.img", ID: "vol0"},
+			data:   []byte("an ext4 filesystem"),
+			source: "/disk.img",
+			format: diskFormatRaw,
+		},
+		{
+			name:   "supported format",
+			vmm:    hypervisors.QemuVmm,
+			block:  types.BlockDevParams{Source: "/disk.qcow2", ID: "blk0"},
+			data:   qcow2Header(3, 0, 0),
+			source: "/disk.qcow2",
+			format: diskFormatQcow2,
+		},
+		{
+			name:   "read-only volume",
+			vmm:    hypervisors.HvtVmm,
+			block:  types.BlockDevParams{Source: "/disk.qcow2", ID: "vol0", ReadOnly: true},
+			data:   qcow2Header(3, 0, 0),
+			source: "/.urunc/disks/vol0.raw",
+			format: diskFormatRaw,
+		},
+		{
+			name:   "writable volume is not probed",
+			vmm:    hypervisors.QemuVmm,
+			block:  types.BlockDevParams{Source: "/disk.img", ID: "vol0"},
+			data:   qcow2Header(3, 0x200, 0),
+			source: "/disk.img",
+			format: diskFormatRaw,
+		},
+		{
+			name:   "writable volume with declared format",
+			vmm:    hypervisors.QemuVmm,
+			block:  types.BlockDevParams{Source: "/disk.qcow2", ID: "vol0", Format: diskFormatQcow2},
+			data:   qcow2Header(3, 0, 0),
+			source: "/disk.qcow2",
+			format: diskFormatQcow2,
+		},
+		{
+			name:     "writable volume with wrong declared format",
+			vmm:      hypervisors.QemuVmm,
+			block:    types.BlockDevParams{Source: "/disk.img", ID: "vol0", Format: diskFormatQcow2},
+			data:     []byte("an ext4 filesystem"),
+			errorMsg: "not a qcow2 image",
+		},
+		{
+			name:     "writable volume",
+			vmm:      hypervisors.HvtVmm,
+			block:    types.BlockDevParams{Source: "/disk.qcow2", ID: "vol0", Format: diskFormatQcow2},
+			data:     qcow2Header(3, 0, 0),
+			errorMsg: "writable volume",
+		},
+		{
+			name:   "writable image of the container",
+			vmm:    hypervisors.HvtVmm,
+			block:  types.BlockDevParams{Source: "/disk.qcow2", ID: "blk0"},
+			data:   qcow2Header(3, 0, 0),
+			source: "/.urunc/disks/blk0.raw",
+			format: diskFormatRaw,
+		},
+		{
+			name:     "invalid image",
+			vmm:      hypervisors.QemuVmm,
+			block:    types.BlockDevParams{Source: "/disk.qcow2", ID: "blk0"},
+			data:     qcow2Header(3, 0x200, 0),
+			errorMsg: "backing file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			monRootfs := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(monRootfs, tc.block.Source), tc.data, 0o644))
+
+			blocks := []types.BlockDevParams{tc.block}
+			err := setupDiskFormats(monRootfs, blocks, newVMM(tc.vmm), qemuImg)
+			if tc.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.source, blocks[0].Source)
+			assert.Equal(t, tc.format, blocks[0].Format)
+			assert.FileExists(t, filepath.Join(monRootfs, blocks[0].Source))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const resolvConfTarget = "/etc/resolv.conf"
+
+// guestDNS returns the IPv4 DNS servers of the guest. These are either the
+// ones in the (decoded) DNS annotation, which overrides the container's
+// resolv.conf, or the nameservers in the resolv.conf that the container
+// engine mounts in the container.
+func guestDNS(annotations map[string]string, mounts []specs.Mount) []string {
+	if servers, ok := annotations[annotDNS]; ok {
+		return filterIPv4(strings.Split(servers, ","))
+	}
+
+	for _, m := range mounts {
+		if m.Destination != resolvConfTarget {
+			continue
+		}
+		servers, err := parseResolvConf(m.Source)
+		if err != nil {
+			uniklog.WithError(err).Warnf("could not read the DNS servers from %s", m.Source)
+			return nil
+		}
+		return servers
+	}
+
+	return nil
+}
+
+// parseResolvConf returns the IPv4 nameservers of the given resolv.conf file.
+func parseResolvConf(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		servers = append(servers, fields[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return filterIPv4(servers), nil
+}
+
+// filterIPv4 returns the valid IPv4 addresses from the given list
+func filterIPv4(addresses []string) []string {
+	var ipv4 []string
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		ip := net.ParseIP(address)
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+		ipv4 = append(ipv4, address)
+	}
+
+	return ipv4
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestDNS(t *testing.T) {
+	t.Parallel()
+	resolvConf := filepath.Join(t.TempDir(), "resolv.conf")
+	content := "# Generated\nsearch default.svc.cluster.local\nnameserver 10.96.0.10\nnameserver fd00::10\n" +
+		"options ndots:5\n  nameserver   1.1.1.1  \nnameserver\n"
+	require.NoError(t, os.WriteFile(resolvConf, []byte(content), 0o644))
+	mounts := []specs.Mount{
+		{Destination: "/etc/hosts", Source: "/nonexistent", Type: "bind"},
+		{Destination: "/etc/resolv.conf", Source: resolvConf, Type: "bind"},
+	}
+
+	t.Run("from resolv.conf", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"10.96.0.10", "1.1.1.1"}, guestDNS(map[string]string{}, mounts))
+	})
+
+	t.Run("annotation overrides resolv.conf", func(t *testing.T) {
+		t.Parallel()
+		annot := map[string]string{annotDNS: "9.9.9.9, invalid,149.112.112.112"}
+		assert.Equal(t, []string{"9.9.9.9", "149.112.112.112"}, guestDNS(annot, mounts))
+	})
+
+	t.Run("no resolv.conf", func(t *testing.T) {
+		t.Parallel()
+		assert.Nil(t, guestDNS(map[string]string{}, mounts[:1]))
+	})
+
+	t.Run("missing resolv.conf", func(t *testing.T) {
+		t.Parallel()
+		missing := []specs.Mount{{Destination: "/etc/resolv.conf", Source: "/nonexistent"}}
+		assert.Nil(t, guestDNS(map[string]string{}, missing))
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const (
+	CloudHypervisorVmm    VmmType = "cloud-hypervisor"
+	CloudHypervisorBinary string  = "cloud-hypervisor"
+	CHSocketFilename      string  = "ch.sock"
+	CHVSockFilename       string  = "ch-vsock.sock"
+	chAPITimeout                  = 2 * time.Second
+)
+
+type CloudHypervisor struct {
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
+}
+
+// Stop presses the virtual power button of the guest through the
+// cloud-hypervisor API socket and falls back to SIGKILL if
+// cloud-hypervisor does not exit within the shutdown timeout.
+func (ch *CloudHypervisor) Stop(pid int) error {
+	return gracefulStop(pid, ch.shutdownTimeout, func() error {
+		return chAPIPut(pid, "vm.power-button")
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (ch *CloudHypervisor) Kill(pid int) error {
+	return killProcess(pid)
+}
+
+// Pause pauses the VM through the cloud-hypervisor API socket
+func (ch *CloudHypervisor) Pause(pid int) error {
+	return chAPIPut(pid, "vm.pause")
+}
+
+// Resume resumes the VM through the cloud-hypervisor API socket
+func (ch *CloudHypervisor) Resume(pid int) error {
+	return chAPIPut(pid, "vm.resume")
+}
+
+// DialVSock connects to the given vsock port of the guest through the unix
+// socket of the vsock device. Cloud-hypervisor uses the same hybrid vsock
+// handshake as Firecracker.
+func (ch *CloudHypervisor) DialVSock(pid int, port uint32) (io.ReadWriteCloser, error) {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return nil, err
+	}
+	args := strings.Split(string(cmdline), "\x00")
+	for i, arg := range args {
+		if arg != "--vsock" || i+1 >= len(args) {
+			continue
+		}
+		for _, opt := range strings.Split(args[i+1], ",") {
+			if socketPath, found := strings.CutPrefix(opt, "socket="); found {
+				return dialFirecrackerVSock(monitorRootfsPath(pid, socketPath), port)
+			}
+		}
+	}
+
+	return nil, ErrNoVSockDevice
+}
+
+func (ch *CloudHypervisor) Ok() error {
+	return nil
+}
+
+// UsesKVM returns a bool value depending on if the monitor uses KVM
+func (ch *CloudHypervisor) UsesKVM() bool {
+	return true
+}
+
+// SupportsSharedfs returns a bool value depending on the monitor support for shared-fs.
+// Cloud-hypervisor supports only virtio-fs.
+func (ch *CloudHypervisor) SupportsSharedfs(fsType string) bool {
+	return fsType == "virtio"
+}
+
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images. Cloud-hypervisor detects the format
+// of raw and qcow2 images by itself.
+func (ch *CloudHypervisor) SupportsDiskFormat(format string) bool {
+	return format == "raw" || format == "qcow2"
+}
+
+func (ch *CloudHypervisor) Path() string {
+	return ch.binaryPath
+}
+
+func (ch *CloudHypervisor) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	exArgs := cloudHypervisorArgs(ch.Path(), args, ukernel)
+	vmmLog.WithField("cloud-hypervisor command", exArgs).Debug("Ready to execve cloud-hypervisor")
+	return syscall.Exec(ch.Path(), exArgs, args.Environment) //nolint: gosec
+}
+
+// cloudHypervisorArgs builds the command line of cloud-hypervisor
+func cloudHypervisorArgs(binaryPath string, args types.ExecArgs, ukernel types.Unikernel) []string {
+	vcpus := args.VCPUs
+	if vcpus == 0 {
+		vcpus = 1
+	}
+	memory := "size=" + BytesToStringMB(args.MemSizeB) + "M"
+	if args.Sharedfs.Type == "virtiofs" {
+		// vhost-user devices require shared memory
+		memory += ",shared=on"
+	}
+	exArgs := []string{
+		binaryPath,
+		"--api-socket", "path=" + filepath.Join(ControlDir, CHSocketFilename),
+		"--kernel", args.UnikernelPath,
+		"--cpus", fmt.Sprintf("boot=%d", vcpus),
+		"--memory", memory,
+		"--serial", "tty",
+		"--console", "off",
+		"--seccomp", strconv.FormatBool(args.Seccomp),
+	}
+
+	// NOTE: Cloud-hypervisor supports only one initramfs. Like in
+	// Firecracker, give priority to the initrd taken from args.
+	extraMonArgs := ukernel.MonitorCli()
+	initrdPath := args.InitrdPath
+	if initrdPath == "" {
+		initrdPath = extraMonArgs.ExtraInitrd
+	}
+	if initrdPath != "" {
+		exArgs = append(exArgs, "--initramfs", initrdPath)
+	}
+
+	if args.Net.TapDev != "" {
+		netcli := ukernel.MonitorNetCli(args.Net.TapDev, args.Net.MAC)
+		if netcli == "" {
+			netcli = "--net tap=" + args.Net.TapDev + ",mac=" + args.Net.MAC
+		}
+		exArgs = append(exArgs, strings.Fields(netcli)...)
+	}
+
+	var disks []string
+	for _, blockArg := range ukernel.MonitorBlockCli() {
+		if blockArg.ExactArgs != "" {
+			exArgs = append(exArgs, strings.Fields(blockArg.ExactArgs)...)
+			continue
+		}
+		if blockArg.ID != "" && blockArg.Path != "" {
+			disk := fmt.Sprintf("path=%s,serial=%s", blockArg.Path, blockArg.ID)
+			if blockArg.ReadOnly {
+				disk += ",readonly=on"
+			}
+			disks = append(disks, disk)
+		}
+	}
+	if len(disks) > 0 {
+		exArgs = append(exArgs, "--disk")
+		exArgs = append(exArgs, disks...)
+	}
+
+	if args.Sharedfs.Type == "virtiofs" {
+		exArgs = append(exArgs, "--fs", "tag=fs0,socket=/tmp/vhostqemu")
+	}
+
+	if args.VAccelType == "vsock" {
+		exArgs = append(exArgs, "--vsock", fmt.Sprintf("cid=%d,socket=%s", args.VSockDevID, args.VSockDevPath+"/vaccel.sock"))
+	} else if args.AgentVSock {
+		exArgs = append(exArgs, "--vsock", fmt.Sprintf("cid=%d,socket=%s", args.VSockDevID, filepath.Join(ControlDir, CHVSockFilename)))
+	}
+
+	exArgs = append(exArgs, strings.Fields(extraMonArgs.OtherArgs)...)
+	if args.Command != "" {
+		exArgs = append(exArgs, "--cmdline", args.Command)
+	}
+
+	return exArgs
+}
+
+// chAPIPut sends a body-less PUT request for the given action to the
+// cloud-hypervisor API socket of the process with the given pid.
+func chAPIPut(pid int, action string) error {
+	socketPath := monitorRootfsPath(pid, filepath.Join(ControlDir, CHSocketFilename))
+	client := &http.Client{
+		Timeout: chAPITimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/api/v1/"+action, http.NoBody)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloud-hypervisor API request %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("cloud-hypervisor API request %s failed with status %d: %s",
+			action, resp.StatusCode, string(bytes.TrimSpace(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestCloudHypervisorArgs(t *testing.T) {
+	t.Parallel()
+	args := types.ExecArgs{
+		UnikernelPath: "/unikernel/vmlinux",
+		Command:       "console=ttyS0 init=/urunit",
+		MemSizeB:      512 * 1000 * 1000,
+		VCPUs:         2,
+		Seccomp:       true,
+		Net:           types.NetDevParams{TapDev: "tap0_urunc", MAC: "aa:bb:cc:dd:ee:ff"},
+	}
+
+	t.Run("complete configuration", func(t *testing.T) {
+		t.Parallel()
+		ukernel := &fakeUnikernel{
+			blockCli: []types.MonitorBlockArgs{
+				{ID: "rootfs", Path: "/dev/dm-1"},
+				{ID: "vol1", Path: "/data.img", ReadOnly: true},
+			},
+			cli: types.MonitorCliArgs{ExtraInitrd: "/urunit.conf"},
+		}
+		assert.Equal(t, []string{
+			"/usr/bin/cloud-hypervisor",
+			"--api-socket", "path=/tmp/urunc/ch.sock",
+			"--kernel", "/unikernel/vmlinux",
+			"--cpus", "boot=2",
+			"--memory", "size=512M",
+			"--serial", "tty",
+			"--console", "off",
+			"--seccomp", "true",
+			"--initramfs", "/urunit.conf",
+			"--net", "tap=tap0_urunc,mac=aa:bb:cc:dd:ee:ff",
+			"--disk", "path=/dev/dm-1,serial=rootfs", "path=/data.img,serial=vol1,readonly=on",
+			"--cmdline", "console=ttyS0 init=/urunit",
+		}, cloudHypervisorArgs("/usr/bin/cloud-hypervisor", args, ukernel))
+	})
+
+	t.Run("virtiofs and agent vsock", func(t *testing.T) {
+		t.Parallel()
+		fsArgs := args
+		fsArgs.Net = types.NetDevParams{}
+		fsArgs.VCPUs = 0
+		fsArgs.Seccomp = false
+		fsArgs.InitrdPath = "/initrd"
+		fsArgs.Sharedfs = types.SharedfsParams{Type: "virtiofs", Path: "/rootfs"}
+		fsArgs.AgentVSock = true
+		fsArgs.VSockDevID = 42
+		ukernel := &fakeUnikernel{cli: types.MonitorCliArgs{ExtraInitrd: "/urunit.conf"}}
+		assert.Equal(t, []string{
+			"/usr/bin/cloud-hypervisor",
+			"--api-socket", "path=/tmp/urunc/ch.sock",
+			"--kernel", "/unikernel/vmlinux",
+			"--cpus", "boot=1",
+			"--memory", "size=512M,shared=on",
+			"--serial", "tty",
+			"--console", "off",
+			"--seccomp", "false",
+			"--initramfs", "/initrd",
+			"--fs", "tag=fs0,socket=/tmp/vhostqemu",
+			"--vsock", "cid=42,socket=/tmp/urunc/ch-vsock.sock",
+			"--cmdline", "console=ttyS0 init=/urunit",
+		}, cloudHypervisorArgs("/usr/bin/cloud-hypervisor", fsArgs, ukernel))
+	})
+
+	t.Run("vaccel vsock", func(t *testing.T) {
+		t.Parallel()
+		vaccelArgs := args
+		vaccelArgs.Net = types.NetDevParams{}
+		vaccelArgs.VAccelType = "vsock"
+		vaccelArgs.VSockDevPath = "/run/vaccel"
+		vaccelArgs.VSockDevID = 7
+		vaccelArgs.AgentVSock = true
+		exArgs := cloudHypervisorArgs("/usr/bin/cloud-hypervisor", vaccelArgs, &fakeUnikernel{})
+		assert.Contains(t, exArgs, "cid=7,socket=/run/vaccel/vaccel.sock")
+		assert.NotContains(t, exArgs, "cid=7,socket=/tmp/urunc/ch-vsock.sock")
+	})
+
+	t.Run("unikernel specific arguments", func(t *testing.T) {
+		t.Parallel()
+		ukernel := &fakeUnikernel{
+			netCli:   "--net tap=tap0_urunc,mac=aa:bb:cc:dd:ee:ff,num_queues=2",
+			blockCli: []types.MonitorBlockArgs{{ExactArgs: "--disk path=/disk.img,readonly=on"}},
+			cli:      types.MonitorCliArgs{OtherArgs: " --rng src=/dev/urandom"},
+		}
+		exArgs := cloudHypervisorArgs("/usr/bin/cloud-hypervisor", args, ukernel)
+		assert.Equal(t, []string{
+			"--net", "tap=tap0_urunc,mac=aa:bb:cc:dd:ee:ff,num_queues=2",
+			"--disk", "path=/disk.img,readonly=on",
+			"--rng", "src=/dev/urandom",
+			"--cmdline", "console=ttyS0 init=/urunit",
+		}, exArgs[len(exArgs)-8:])
+	})
+}
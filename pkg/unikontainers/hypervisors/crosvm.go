@@ -0,0 +1,209 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const (
+	CrosvmVmm    VmmType = "crosvm"
+	CrosvmBinary string  = "crosvm"
+	// The control socket of crosvm, inside the control directory
+	crosvmSocketFilename = "crosvm.sock"
+	// The empty directory where crosvm pivots the sandboxed devices to
+	crosvmPivotRoot      = "/var/empty"
+	crosvmControlTimeout = 5 * time.Second
+)
+
+type Crosvm struct {
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
+}
+
+// Stop presses the virtual power button of the guest through the control
+// socket of crosvm and falls back to SIGKILL if crosvm does not exit within
+// the shutdown timeout.
+func (c *Crosvm) Stop(pid int) error {
+	return gracefulStop(pid, c.shutdownTimeout, func() error {
+		return c.control(pid, "powerbtn")
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (c *Crosvm) Kill(pid int) error {
+	return killProcess(pid)
+}
+
+// Pause suspends the vCPUs of the VM through the control socket of crosvm
+func (c *Crosvm) Pause(pid int) error {
+	return c.control(pid, "suspend")
+}
+
+// Resume resumes the vCPUs of the VM through the control socket of crosvm
+func (c *Crosvm) Resume(pid int) error {
+	return c.control(pid, "resume")
+}
+
+// DialVSock connects to the given port of the guest through the
+// vhost-vsock device of the host.
+func (c *Crosvm) DialVSock(pid int, port uint32) (io.ReadWriteCloser, error) {
+	cid, err := guestCID(pid, "cid")
+	if err != nil {
+		return nil, err
+	}
+
+	return dialVSock(cid, port)
+}
+
+func (c *Crosvm) Ok() error {
+	return nil
+}
+
+// UsesKVM returns a bool value depending on if the monitor uses KVM
+func (c *Crosvm) UsesKVM() bool {
+	return true
+}
+
+// SupportsSharedfs returns a bool value depending on the monitor support for shared-fs
+func (c *Crosvm) SupportsSharedfs(_ string) bool {
+	return true
+}
+
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images. Crosvm detects the format of raw and
+// qcow2 images by itself.
+func (c *Crosvm) SupportsDiskFormat(format string) bool {
+	return format == "raw" || format == "qcow2"
+}
+
+func (c *Crosvm) Path() string {
+	return c.binaryPath
+}
+
+func (c *Crosvm) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	if args.Seccomp {
+		// The sandbox of crosvm requires an empty directory to pivot to
+		err := os.MkdirAll(crosvmPivotRoot, 0o755)
+		if err != nil {
+			return fmt.Errorf("failed to create crosvm pivot root: %w", err)
+		}
+	}
+	exArgs := crosvmArgs(c.binaryPath, args, ukernel)
+	vmmLog.WithField("crosvm command", exArgs).Debug("Ready to execve crosvm")
+	return syscall.Exec(c.binaryPath, exArgs, args.Environment) //nolint: gosec
+}
+
+// crosvmArgs builds the command line of crosvm
+func crosvmArgs(binaryPath string, args types.ExecArgs, ukernel types.Unikernel) []string {
+	exArgs := []string{
+		binaryPath, "run",
+		"--mem", BytesToStringMB(args.MemSizeB),
+		"--socket", filepath.Join(ControlDir, crosvmSocketFilename),
+		"--serial", "type=stdout,hardware=serial,num=1,console=true,stdin=true",
+		"--no-balloon",
+	}
+	if args.VCPUs > 0 {
+		exArgs = append(exArgs, "--cpus", fmt.Sprintf("%d", args.VCPUs))
+	}
+
+	// Without seccomp, disable the whole sandbox of crosvm, since the
+	// seccomp filters of the devices are part of it. Otherwise, use the
+	// seccomp policies that crosvm has been built with.
+	if args.Seccomp {
+		exArgs = append(exArgs, "--jail", "pivot-root="+crosvmPivotRoot)
+	} else {
+		exArgs = append(exArgs, "--disable-sandbox")
+	}
+
+	// NOTE: Crosvm supports only one initrd. Like in Firecracker, give
+	// priority to the initrd taken from args.
+	extraMonArgs := ukernel.MonitorCli()
+	initrdPath := args.InitrdPath
+	if initrdPath == "" {
+		initrdPath = extraMonArgs.ExtraInitrd
+	}
+	if initrdPath != "" {
+		exArgs = append(exArgs, "--initrd", initrdPath)
+	}
+
+	if args.Net.TapDev != "" {
+		netcli := ukernel.MonitorNetCli(args.Net.TapDev, args.Net.MAC)
+		if netcli == "" {
+			netcli = "--net tap-name=" + args.Net.TapDev + ",mac=" + args.Net.MAC
+		}
+		exArgs = append(exArgs, strings.Fields(netcli)...)
+	}
+
+	for _, blockArg := range ukernel.MonitorBlockCli() {
+		if blockArg.ExactArgs != "" {
+			exArgs = append(exArgs, strings.Fields(blockArg.ExactArgs)...)
+			continue
+		}
+		if blockArg.ID != "" && blockArg.Path != "" {
+			block := fmt.Sprintf("path=%s,id=%s", blockArg.Path, blockArg.ID)
+			if blockArg.ReadOnly {
+				block += ",ro=true"
+			}
+			exArgs = append(exArgs, "--block", block)
+		}
+	}
+
+	switch args.Sharedfs.Type {
+	case "9pfs":
+		exArgs = append(exArgs, "--shared-dir", args.Sharedfs.Path+":fs0:type=p9")
+	case "virtiofs":
+		// Use the virtiofsd instance that urunc spawns for the container
+		exArgs = append(exArgs, "--vhost-user-fs", "/tmp/vhostqemu:fs0")
+	default:
+		// Nothing to add
+	}
+
+	if args.VAccelType == "vsock" || args.AgentVSock {
+		exArgs = append(exArgs, "--vsock", fmt.Sprintf("cid=%d", args.VSockDevID))
+	}
+
+	exArgs = append(exArgs, strings.Fields(extraMonArgs.OtherArgs)...)
+	if args.Command != "" {
+		exArgs = append(exArgs, "--params", args.Command)
+	}
+
+	return append(exArgs, args.UnikernelPath)
+}
+
+// control runs the given control command of crosvm against the control
+// socket of the crosvm process with the given pid.
+func (c *Crosvm) control(pid int, command string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), crosvmControlTimeout)
+	defer cancel()
+	socketPath := monitorRootfsPath(pid, filepath.Join(ControlDir, crosvmSocketFilename))
+	out, err := exec.CommandContext(ctx, c.binaryPath, command, socketPath).CombinedOutput() //nolint: gosec
+	if err != nil {
+		return fmt.Errorf("crosvm %s failed: %w: %s", command, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestCrosvmArgs(t *testing.T) {
+	t.Parallel()
+	args := types.ExecArgs{
+		UnikernelPath: "/unikernel/vmlinux",
+		Command:       "console=ttyS0 init=/urunit",
+		MemSizeB:      512 * 1000 * 1000,
+		VCPUs:         2,
+		Seccomp:       true,
+		Net:           types.NetDevParams{TapDev: "tap0_urunc", MAC: "aa:bb:cc:dd:ee:ff"},
+	}
+
+	t.Run("complete configuration", func(t *testing.T) {
+		t.Parallel()
+		ukernel := &fakeUnikernel{
+			blockCli: []types.MonitorBlockArgs{
+				{ID: "rootfs", Path: "/dev/dm-1"},
+				{ID: "vol1", Path: "/data.img", ReadOnly: true},
+			},
+			cli: types.MonitorCliArgs{ExtraInitrd: "/urunit.conf"},
+		}
+		assert.Equal(t, []string{
+			"/usr/bin/crosvm", "run",
+			"--mem", "512",
+			"--socket", "/tmp/urunc/crosvm.sock",
+			"--serial", "type=stdout,hardware=serial,num=1,console=true,stdin=true",
+			"--no-balloon",
+			"--cpus", "2",
+			"--jail", "pivot-root=/var/empty",
+			"--initrd", "/urunit.conf",
+			"--net", "tap-name=tap0_urunc,mac=aa:bb:cc:dd:ee:ff",
+			"--block", "path=/dev/dm-1,id=rootfs",
+			"--block", "path=/data.img,id=vol1,ro=true",
+			"--params", "console=ttyS0 init=/urunit",
+			"/unikernel/vmlinux",
+		}, crosvmArgs("/usr/bin/crosvm", args, ukernel))
+	})
+
+	t.Run("no seccomp", func(t *testing.T) {
+		t.Parallel()
+		noSeccomp := args
+		noSeccomp.Seccomp = false
+		exArgs := crosvmArgs("/usr/bin/crosvm", noSeccomp, &fakeUnikernel{})
+		assert.Contains(t, exArgs, "--disable-sandbox")
+		assert.NotContains(t, exArgs, "--jail")
+	})
+
+	t.Run("shared-fs", func(t *testing.T) {
+		t.Parallel()
+		for fsType, expected := range map[string][]string{
+			"9pfs":     {"--shared-dir", "/rootfs:fs0:type=p9"},
+			"virtiofs": {"--vhost-user-fs", "/tmp/vhostqemu:fs0"},
+		} {
+			fsArgs := args
+			fsArgs.Sharedfs = types.SharedfsParams{Type: fsType, Path: "/rootfs"}
+			exArgs := crosvmArgs("/usr/bin/crosvm", fsArgs, &fakeUnikernel{})
+			assert.Subset(t, exArgs, expected, fsType)
+		}
+	})
+
+	t.Run("initrd and vsock", func(t *testing.T) {
+		t.Parallel()
+		vsockArgs := args
+		vsockArgs.Net = types.NetDevParams{}
+		vsockArgs.VCPUs = 0
+		vsockArgs.Command = ""
+		vsockArgs.InitrdPath = "/initrd"
+		vsockArgs.AgentVSock = true
+		vsockArgs.VSockDevID = 42
+		ukernel := &fakeUnikernel{cli: types.MonitorCliArgs{ExtraInitrd: "/urunit.conf", OtherArgs: " --rng"}}
+		assert.Equal(t, []string{
+			"/usr/bin/crosvm", "run",
+			"--mem", "512",
+			"--socket", "/tmp/urunc/crosvm.sock",
+			"--serial", "type=stdout,hardware=serial,num=1,console=true,stdin=true",
+			"--no-balloon",
+			"--jail", "pivot-root=/var/empty",
+			"--initrd", "/initrd",
+			"--vsock", "cid=42",
+			"--rng",
+			"/unikernel/vmlinux",
+		}, crosvmArgs("/usr/bin/crosvm", vsockArgs, ukernel))
+	})
+}
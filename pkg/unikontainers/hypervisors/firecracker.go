@@ -17,10 +17,12 @@ package hypervisors
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
 )
@@ -29,11 +31,17 @@ const (
 	FirecrackerVmm    VmmType = "firecracker"
 	FirecrackerBinary string  = "firecracker"
 	FCJsonFilename    string  = "fc.json"
+	FCSocketFilename  string  = "fc.sock"
+	FCMetricsFilename string  = "fc-metrics.json"
+	FCVSockFilename   string  = "vsock.sock"
+	// The file where urunc keeps the totals of the Firecracker metrics
+	FCMetricsStateFilename string = "fc-metrics-state.json"
 )
 
 type Firecracker struct {
-	binaryPath string
-	binary     string
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
 }
 
 type FirecrackerBootSource struct {
@@ -74,12 +82,77 @@ type FirecrackerConfig struct {
 	Drives  []FirecrackerDrive    `json:"drives"`
 	NetIfs  []FirecrackerNet      `json:"network-interfaces,omitempty"`
 	VSock   FirecrackerVSockDev   `json:"vsock,omitempty"`
+	Metrics *FirecrackerMetrics   `json:"metrics,omitempty"`
 }
 
+// Stop sends a Ctrl+Alt+Del to the guest through the Firecracker API socket
+// and falls back to SIGKILL if Firecracker does not exit within the shutdown
+// timeout.
 func (fc *Firecracker) Stop(pid int) error {
+	return gracefulStop(pid, fc.shutdownTimeout, func() error {
+		return FirecrackerClientForPid(pid).SendCtrlAltDel()
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (fc *Firecracker) Kill(pid int) error {
 	return killProcess(pid)
 }
 
+// Pause pauses the microVM through the Firecracker API socket
+func (fc *Firecracker) Pause(pid int) error {
+	return FirecrackerClientForPid(pid).PatchVMState(FCVMStatePaused)
+}
+
+// Resume resumes the microVM through the Firecracker API socket
+func (fc *Firecracker) Resume(pid int) error {
+	return FirecrackerClientForPid(pid).PatchVMState(FCVMStateResumed)
+}
+
+// DialVSock connects to the given vsock port of the guest through the unix
+// socket of the vsock device in the Firecracker config.
+func (fc *Firecracker) DialVSock(pid int, port uint32) (io.ReadWriteCloser, error) {
+	data, err := os.ReadFile(monitorRootfsPath(pid, filepath.Join("/tmp/", FCJsonFilename)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Firecracker json config: %w", err)
+	}
+	var config FirecrackerConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Firecracker json config: %w", err)
+	}
+	if config.VSock.UDSPath == "" {
+		return nil, ErrNoVSockDevice
+	}
+
+	return dialFirecrackerVSock(monitorRootfsPath(pid, config.VSock.UDSPath), port)
+}
+
+// Stats flushes the metrics of Firecracker and returns the accumulated
+// network, block and vCPU counters
+func (fc *Firecracker) Stats(pid int) (map[string]uint64, error) {
+	err := FirecrackerClientForPid(pid).FlushMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to flush Firecracker metrics: %w", err)
+	}
+	statePath := monitorRootfsPath(pid, filepath.Join(ControlDir, FCMetricsStateFilename))
+
+	return collectFirecrackerMetrics(FirecrackerMetricsPath(pid), statePath)
+}
+
+// FirecrackerClientForPid returns a client for the API socket of the
+// Firecracker process with the given pid.
+func FirecrackerClientForPid(pid int) *FirecrackerClient {
+	socketPath := monitorRootfsPath(pid, filepath.Join(ControlDir, FCSocketFilename))
+	return NewFirecrackerClient(socketPath, fcDefaultAPITimeout)
+}
+
+// FirecrackerMetricsPath returns the host path of the metrics file of the
+// Firecracker process with the given pid.
+func FirecrackerMetricsPath(pid int) string {
+	return monitorRootfsPath(pid, filepath.Join(ControlDir, FCMetricsFilename))
+}
+
 func (fc *Firecracker) Ok() error {
 	return nil
 }
@@ -93,6 +166,12 @@ func (fc *Firecracker) SupportsSharedfs(_ string) bool {
 	return false
 }
 
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images
+func (fc *Firecracker) SupportsDiskFormat(format string) bool {
+	return format == "raw"
+}
+
 func (fc *Firecracker) Path() string {
 	return fc.binaryPath
 }
@@ -104,9 +183,11 @@ func (fc *Firecracker) Execve(args types.ExecArgs, ukernel types.Unikernel) erro
 	// options in FC, since the string return value of the Monitor related
 	// functions in the unikernel interface do not integrate well with FC's
 	// json configuration.
-	cmdString := fc.Path() + " --no-api --config-file "
+	// Firecracker boots the microVM using the configuration file, but it
+	// also serves its API, so that we can control the microVM afterwards.
+	cmdString := fc.Path() + " --api-sock " + filepath.Join(ControlDir, FCSocketFilename)
 	JSONConfigFile := filepath.Join("/tmp/", FCJsonFilename)
-	cmdString += JSONConfigFile
+	cmdString += " --config-file " + JSONConfigFile
 	if !args.Seccomp {
 		cmdString += " --no-seccomp"
 	}
@@ -156,7 +237,7 @@ func (fc *Firecracker) Execve(args types.ExecArgs, ukernel types.Unikernel) erro
 	for _, blockArg := range bArgs {
 		aBlock := FirecrackerDrive{
 			DriveID:   blockArg.ID,
-			IsRO:      false,
+			IsRO:      blockArg.ReadOnly,
 			IsRootDev: false,
 			HostPath:  blockArg.Path,
 		}
@@ -178,7 +259,21 @@ func (fc *Firecracker) Execve(args types.ExecArgs, ukernel types.Unikernel) erro
 			UDSPath:  args.VSockDevPath + "/vaccel.sock",
 			VSockID:  "root",
 		}
+	} else if args.AgentVSock {
+		FCVSockDev = FirecrackerVSockDev{
+			GuestCID: args.VSockDevID,
+			UDSPath:  filepath.Join(ControlDir, FCVSockFilename),
+			VSockID:  "root",
+		}
+	}
+
+	// Firecracker expects the metrics file to exist
+	metricsPath := filepath.Join(ControlDir, FCMetricsFilename)
+	metricsFile, err := os.OpenFile(metricsPath, os.O_CREATE|os.O_WRONLY, 0o644) //nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to create Firecracker metrics file: %w", err)
 	}
+	metricsFile.Close()
 
 	FCConfig := &FirecrackerConfig{
 		Source:  FCSource,
@@ -186,6 +281,7 @@ func (fc *Firecracker) Execve(args types.ExecArgs, ukernel types.Unikernel) erro
 		Drives:  FCDrives,
 		NetIfs:  FCNet,
 		VSock:   FCVSockDev,
+		Metrics: &FirecrackerMetrics{MetricsPath: metricsPath},
 	}
 	FCConfigJSON, _ := json.Marshal(FCConfig)
 	if err := os.WriteFile(JSONConfigFile, FCConfigJSON, 0o644); err != nil { //nolint: gosec
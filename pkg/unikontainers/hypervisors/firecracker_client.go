@@ -0,0 +1,227 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const fcDefaultAPITimeout = 2 * time.Second
+
+// Firecracker instance actions
+const (
+	FCActionInstanceStart  = "InstanceStart"
+	FCActionSendCtrlAltDel = "SendCtrlAltDel"
+	FCActionFlushMetrics   = "FlushMetrics"
+)
+
+// Firecracker microVM states for PATCH /vm
+const (
+	FCVMStatePaused  = "Paused"
+	FCVMStateResumed = "Resumed"
+)
+
+type FirecrackerAction struct {
+	ActionType string `json:"action_type"`
+}
+
+type FirecrackerVMState struct {
+	State string `json:"state"`
+}
+
+type FirecrackerMetrics struct {
+	MetricsPath string `json:"metrics_path"`
+}
+
+type FirecrackerDrivePatch struct {
+	DriveID  string `json:"drive_id"`
+	HostPath string `json:"path_on_host,omitempty"`
+}
+
+// FirecrackerInstanceInfo is the general information of a microVM, as
+// returned by GET /
+type FirecrackerInstanceInfo struct {
+	ID         string `json:"id"`
+	State      string `json:"state"`
+	VMMVersion string `json:"vmm_version"`
+	AppName    string `json:"app_name"`
+}
+
+// FirecrackerAPIError is the error returned by the Firecracker API server
+type FirecrackerAPIError struct {
+	StatusCode   int
+	FaultMessage string `json:"fault_message"`
+}
+
+func (e *FirecrackerAPIError) Error() string {
+	return fmt.Sprintf("firecracker API error (status %d): %s", e.StatusCode, e.FaultMessage)
+}
+
+// FirecrackerClient is a client for the REST API that Firecracker serves
+// over its API unix socket.
+type FirecrackerClient struct {
+	client *http.Client
+}
+
+// NewFirecrackerClient returns a client for the Firecracker API server
+// listening on socketPath.
+func NewFirecrackerClient(socketPath string, timeout time.Duration) *FirecrackerClient {
+	return &FirecrackerClient{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// do sends a request with the JSON encoded body to the Firecracker API
+// server and decodes the response into result, if result is not nil.
+func (c *FirecrackerClient) do(method string, path string, body any, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, "http://localhost"+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("firecracker API request %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		apiErr := &FirecrackerAPIError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil {
+			apiErr.FaultMessage = string(data)
+		}
+		return apiErr
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// DescribeInstance returns the general information of the microVM
+func (c *FirecrackerClient) DescribeInstance() (FirecrackerInstanceInfo, error) {
+	var info FirecrackerInstanceInfo
+	err := c.do(http.MethodGet, "/", nil, &info)
+	return info, err
+}
+
+// PutBootSource sets the kernel, initrd and boot arguments of the microVM.
+// It is only allowed before the microVM starts.
+func (c *FirecrackerClient) PutBootSource(source FirecrackerBootSource) error {
+	return c.do(http.MethodPut, "/boot-source", source, nil)
+}
+
+// GetMachineConfig returns the machine configuration of the microVM
+func (c *FirecrackerClient) GetMachineConfig() (FirecrackerMachine, error) {
+	var machine FirecrackerMachine
+	err := c.do(http.MethodGet, "/machine-config", nil, &machine)
+	return machine, err
+}
+
+// PutMachineConfig sets the machine configuration of the microVM.
+// It is only allowed before the microVM starts.
+func (c *FirecrackerClient) PutMachineConfig(machine FirecrackerMachine) error {
+	return c.do(http.MethodPut, "/machine-config", machine, nil)
+}
+
+// PutDrive creates or updates a drive. It is only allowed before the
+// microVM starts.
+func (c *FirecrackerClient) PutDrive(drive FirecrackerDrive) error {
+	return c.do(http.MethodPut, "/drives/"+url.PathEscape(drive.DriveID), drive, nil)
+}
+
+// PatchDrive updates the backing file of a drive of a running microVM
+func (c *FirecrackerClient) PatchDrive(driveID string, hostPath string) error {
+	patch := FirecrackerDrivePatch{
+		DriveID:  driveID,
+		HostPath: hostPath,
+	}
+	return c.do(http.MethodPatch, "/drives/"+url.PathEscape(driveID), patch, nil)
+}
+
+// PutNetworkInterface creates a network interface. It is only allowed
+// before the microVM starts.
+func (c *FirecrackerClient) PutNetworkInterface(netIf FirecrackerNet) error {
+	return c.do(http.MethodPut, "/network-interfaces/"+url.PathEscape(netIf.IfaceID), netIf, nil)
+}
+
+// PutVSock sets the vsock device of the microVM. It is only allowed before
+// the microVM starts.
+func (c *FirecrackerClient) PutVSock(vsock FirecrackerVSockDev) error {
+	return c.do(http.MethodPut, "/vsock", vsock, nil)
+}
+
+// PutMetrics sets the file where Firecracker writes its metrics
+func (c *FirecrackerClient) PutMetrics(metrics FirecrackerMetrics) error {
+	return c.do(http.MethodPut, "/metrics", metrics, nil)
+}
+
+// CreateAction executes the given instance action
+func (c *FirecrackerClient) CreateAction(actionType string) error {
+	return c.do(http.MethodPut, "/actions", FirecrackerAction{ActionType: actionType}, nil)
+}
+
+// InstanceStart boots the microVM
+func (c *FirecrackerClient) InstanceStart() error {
+	return c.CreateAction(FCActionInstanceStart)
+}
+
+// SendCtrlAltDel sends a Ctrl+Alt+Del to the guest (x86 only)
+func (c *FirecrackerClient) SendCtrlAltDel() error {
+	return c.CreateAction(FCActionSendCtrlAltDel)
+}
+
+// FlushMetrics asks Firecracker to write its metrics to the metrics file
+func (c *FirecrackerClient) FlushMetrics() error {
+	return c.CreateAction(FCActionFlushMetrics)
+}
+
+// PatchVMState pauses or resumes the microVM
+func (c *FirecrackerClient) PatchVMState(state string) error {
+	return c.do(http.MethodPatch, "/vm", FirecrackerVMState{State: state}, nil)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fcRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// stubFirecrackerServer serves HTTP over a unix socket, like the Firecracker
+// API server does. It records every request and replies with the handler.
+func stubFirecrackerServer(t *testing.T, handler http.HandlerFunc) (*FirecrackerClient, <-chan fcRequest) {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), FCSocketFilename)
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	requests := make(chan fcRequest, 16)
+	srv := &http.Server{ //nolint: gosec
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := fcRequest{Method: r.Method, Path: r.URL.Path}
+			data, _ := io.ReadAll(r.Body)
+			if len(data) > 0 {
+				_ = json.Unmarshal(data, &req.Body)
+			}
+			requests <- req
+			if handler == nil {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			handler(w, r)
+		}),
+	}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { srv.Close() })
+
+	return NewFirecrackerClient(socketPath, fcDefaultAPITimeout), requests
+}
+
+func TestFirecrackerClientRequests(t *testing.T) {
+	t.Parallel()
+	client, requests := stubFirecrackerServer(t, nil)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected fcRequest
+	}{
+		{
+			name: "machine config",
+			call: func() error {
+				return client.PutMachineConfig(FirecrackerMachine{VcpuCount: 2, MemSizeMiB: 512})
+			},
+			expected: fcRequest{http.MethodPut, "/machine-config", map[string]any{
+				"vcpu_count": 2.0, "mem_size_mib": 512.0, "smt": false, "track_dirty_pages": false,
+			}},
+		},
+		{
+			name: "drive",
+			call: func() error {
+				return client.PutDrive(FirecrackerDrive{DriveID: "rootfs", IsRootDev: true, HostPath: "/rootfs.img"})
+			},
+			expected: fcRequest{http.MethodPut, "/drives/rootfs", map[string]any{
+				"drive_id": "rootfs", "is_read_only": false, "is_root_device": true, "path_on_host": "/rootfs.img",
+			}},
+		},
+		{
+			name: "patch drive",
+			call: func() error {
+				return client.PatchDrive("vol1", "/new.img")
+			},
+			expected: fcRequest{http.MethodPatch, "/drives/vol1", map[string]any{
+				"drive_id": "vol1", "path_on_host": "/new.img",
+			}},
+		},
+		{
+			name: "network interface",
+			call: func() error {
+				return client.PutNetworkInterface(FirecrackerNet{IfaceID: "net1", HostIF: "tap0"})
+			},
+			expected: fcRequest{http.MethodPut, "/network-interfaces/net1", map[string]any{
+				"iface_id": "net1", "host_dev_name": "tap0",
+			}},
+		},
+		{
+			name: "vsock",
+			call: func() error {
+				return client.PutVSock(FirecrackerVSockDev{GuestCID: 3, UDSPath: "/tmp/v.sock", VSockID: "root"})
+			},
+			expected: fcRequest{http.MethodPut, "/vsock", map[string]any{
+				"guest_cid": 3.0, "uds_path": "/tmp/v.sock", "vsock_id": "root",
+			}},
+		},
+		{
+			name: "metrics",
+			call: func() error {
+				return client.PutMetrics(FirecrackerMetrics{MetricsPath: "/tmp/metrics"})
+			},
+			expected: fcRequest{http.MethodPut, "/metrics", map[string]any{"metrics_path": "/tmp/metrics"}},
+		},
+		{
+			name:     "ctrl alt del",
+			call:     client.SendCtrlAltDel,
+			expected: fcRequest{http.MethodPut, "/actions", map[string]any{"action_type": "SendCtrlAltDel"}},
+		},
+		{
+			name:     "flush metrics",
+			call:     client.FlushMetrics,
+			expected: fcRequest{http.MethodPut, "/actions", map[string]any{"action_type": "FlushMetrics"}},
+		},
+		{
+			name: "pause",
+			call: func() error {
+				return client.PatchVMState(FCVMStatePaused)
+			},
+			expected: fcRequest{http.MethodPatch, "/vm", map[string]any{"state": "Paused"}},
+		},
+	}
+
+	for _, tc := range tests {
+		err := tc.call()
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, <-requests, tc.name)
+	}
+}
+
+func TestFirecrackerClientResponses(t *testing.T) {
+	t.Run("describe instance", func(t *testing.T) {
+		t.Parallel()
+		client, requests := stubFirecrackerServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"anonymous-instance","state":"Running","vmm_version":"1.7.0","app_name":"Firecracker"}`))
+		})
+
+		info, err := client.DescribeInstance()
+		assert.NoError(t, err)
+		assert.Equal(t, "Running", info.State)
+		assert.Equal(t, "1.7.0", info.VMMVersion)
+		req := <-requests
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/", req.Path)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		t.Parallel()
+		client, _ := stubFirecrackerServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"fault_message":"The requested operation is not supported after starting the microVM."}`))
+		})
+
+		err := client.PutMachineConfig(FirecrackerMachine{VcpuCount: 1})
+		var apiErr *FirecrackerAPIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.Contains(t, apiErr.FaultMessage, "not supported")
+	})
+
+	t.Run("no server", func(t *testing.T) {
+		t.Parallel()
+		client := NewFirecrackerClient(filepath.Join(t.TempDir(), "missing.sock"), fcDefaultAPITimeout)
+		assert.Error(t, client.SendCtrlAltDel())
+	})
+}
@@ -15,30 +15,93 @@
 package hypervisors
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	hedge "github.com/nubificus/hedge_cli/hedge_api"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
 )
 
 const (
 	HedgeVmm         VmmType = "hedge"
 	maxVMListRetries int     = 20
 	ConsoleEndpoint          = "/proc/vmcons"
+	// The file inside the control directory that holds the name of the VM
+	hedgeVMFilename   = "hedge-vm"
+	hedgePollInterval = 100 * time.Millisecond
 )
 
-type Hedge struct{}
+var ErrHedgeVMNotStarted = errors.New("hedge VM did not start")
+
+// hedgeControl is the control interface of the hedge kernel module
+type hedgeControl interface {
+	Status() error
+	StartVM(hedge.VMConfig) error
+	StopVM(string) error
+	ListVMs() ([]hedge.VM, error)
+}
+
+// hedgeAPI controls hedge through the /proc endpoints of the hedge library
+type hedgeAPI struct{}
+
+func (hedgeAPI) Status() error                       { return hedge.Status() }
+func (hedgeAPI) StartVM(config hedge.VMConfig) error { return hedge.StartVM(config) }
+func (hedgeAPI) StopVM(name string) error            { return hedge.StopVM(name) }
+func (hedgeAPI) ListVMs() ([]hedge.VM, error)        { return hedge.ListVMs() }
+
+// Hedge runs the guest as a VM of the hedge kernel module. Since there is
+// no monitor process, the urunc process that starts the VM keeps running
+// until the VM stops, in order to represent the VM in the container.
+type Hedge struct {
+	ctl             hedgeControl
+	shutdownTimeout time.Duration
+}
+
+func (h *Hedge) control() hedgeControl {
+	if h.ctl == nil {
+		return hedgeAPI{}
+	}
+	return h.ctl
+}
 
 func (h *Hedge) Ok() error {
-	return fmt.Errorf("hedge not implemented yet")
+	return h.control().Status()
 }
 
-func (h *Hedge) Stop(_ int) error {
-	return fmt.Errorf("hedge not implemented yet")
+// Stop asks hedge to stop the VM and waits for the process that represents
+// the VM to exit, falling back to SIGKILL after the shutdown timeout.
+func (h *Hedge) Stop(pid int) error {
+	name, err := hedgeVMName(pid)
+	if err != nil {
+		return err
+	}
+	return gracefulStop(pid, h.shutdownTimeout, func() error {
+		return h.control().StopVM(name)
+	})
 }
 
+// Kill stops the VM and kills the process that represents it immediately
+func (h *Hedge) Kill(pid int) error {
+	name, err := hedgeVMName(pid)
+	if err == nil {
+		err = h.control().StopVM(name)
+	}
+	if err != nil {
+		vmmLog.WithError(err).Warn("failed to stop hedge VM")
+	}
+
+	return killProcess(pid)
+}
+
+// UsesKVM returns false, since hedge is a hypervisor on its own
 func (h *Hedge) UsesKVM() bool {
-	return true
+	return false
 }
 
 // SupportsSharedfs returns a bool value depending on the monitor support for shared-fs
@@ -46,23 +109,158 @@ func (h *Hedge) SupportsSharedfs(_ string) bool {
 	return false
 }
 
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images
+func (h *Hedge) SupportsDiskFormat(format string) bool {
+	return format == "raw"
+}
+
+// Path returns an empty path, since hedge has no monitor binary
 func (h *Hedge) Path() string {
 	return ""
 }
 
-func (h *Hedge) Execve(_ types.ExecArgs, _ types.Unikernel) error {
-	return fmt.Errorf("hedge not implemented yet")
+// Execve starts the VM through hedge and waits until it stops. Contrary to
+// the other monitors, it returns when the VM stops.
+func (h *Hedge) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	config, err := hedgeVMConfig(args, ukernel)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(ControlDir, hedgeVMFilename), []byte(config.Name), 0o644) //nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to save hedge VM name: %w", err)
+	}
+
+	vmmLog.WithField("hedge config", config).Debug("Ready to start hedge VM")
+	err = h.control().StartVM(config)
+	if err != nil {
+		return fmt.Errorf("failed to start hedge VM: %w", err)
+	}
+
+	return h.waitVM(config.Name)
 }
 
-func (h *Hedge) VMState(name string) string {
-	vms, err := hedge.ListVMs()
+// waitVM waits until the VM with the given name appears in the list of
+// hedge VMs and then until it stops. If the process receives SIGTERM or
+// SIGINT in the meantime, it stops the VM.
+func (h *Hedge) waitVM(name string) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, unix.SIGTERM, unix.SIGINT)
+	defer signal.Stop(sigs)
+
+	started := false
+	for retries := 0; ; {
+		exists, err := h.vmExists(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case exists:
+			started = true
+		case started:
+			vmmLog.WithField("vm", name).Debug("hedge VM stopped")
+			return nil
+		default:
+			retries++
+			if retries >= maxVMListRetries {
+				return fmt.Errorf("%w: %s", ErrHedgeVMNotStarted, name)
+			}
+		}
+
+		select {
+		case sig := <-sigs:
+			vmmLog.WithField("vm", name).Debugf("received %v, stopping hedge VM", sig)
+			err = h.control().StopVM(name)
+			if err != nil {
+				return fmt.Errorf("failed to stop hedge VM: %w", err)
+			}
+		case <-time.After(hedgePollInterval):
+		}
+	}
+}
+
+func (h *Hedge) vmExists(name string) (bool, error) {
+	vms, err := h.control().ListVMs()
 	if err != nil {
-		return "error"
+		return false, fmt.Errorf("failed to list hedge VMs: %w", err)
 	}
 	for _, vm := range vms {
 		if vm.Name == name {
-			return "running"
+			return true, nil
 		}
 	}
+
+	return false, nil
+}
+
+func (h *Hedge) VMState(name string) string {
+	exists, err := h.vmExists(name)
+	if err != nil {
+		return "error"
+	}
+	if exists {
+		return "running"
+	}
 	return "unknown"
 }
+
+// hedgeVMConfig builds the hedge description of the VM. Hedge supports a
+// single block device and a single network interface.
+func hedgeVMConfig(args types.ExecArgs, ukernel types.Unikernel) (hedge.VMConfig, error) {
+	if args.InitrdPath != "" || ukernel.MonitorCli().ExtraInitrd != "" {
+		return hedge.VMConfig{}, fmt.Errorf("hedge does not support initrd")
+	}
+	if args.Sharedfs.Type != "" {
+		return hedge.VMConfig{}, fmt.Errorf("hedge does not support shared-fs")
+	}
+
+	mem := bytesToMB(args.MemSizeB)
+	if mem == 0 {
+		mem = DefaultMemory
+	}
+	config := hedge.VMConfig{
+		Name:    args.ContainerID,
+		Binary:  args.UnikernelPath,
+		Mem:     int(mem), //nolint: gosec
+		CmdLine: args.Command,
+	}
+
+	if args.Net.TapDev != "" {
+		config.Net = ukernel.MonitorNetCli(args.Net.TapDev, args.Net.MAC)
+		if config.Net == "" {
+			config.Net = args.Net.TapDev
+		}
+	}
+
+	blockArgs := ukernel.MonitorBlockCli()
+	if len(blockArgs) > 1 {
+		return hedge.VMConfig{}, fmt.Errorf("hedge supports a single block device, got %d", len(blockArgs))
+	}
+	if len(blockArgs) == 1 {
+		if blockArgs[0].ReadOnly {
+			return hedge.VMConfig{}, fmt.Errorf("%w: hedge can not attach %s read-only", ErrReadOnlyBlock, blockArgs[0].Path)
+		}
+		config.Blk = blockArgs[0].Path
+	}
+
+	// The hedge control interface separates the fields with '|'
+	for _, field := range []string{config.Name, config.Binary, config.Blk, config.Net, config.CmdLine} {
+		if strings.ContainsAny(field, "|\n") {
+			return hedge.VMConfig{}, fmt.Errorf("invalid hedge VM configuration value %q", field)
+		}
+	}
+
+	return config, config.Validate()
+}
+
+// hedgeVMName returns the name of the hedge VM that the process with the
+// given pid represents.
+func hedgeVMName(pid int) (string, error) {
+	name, err := os.ReadFile(monitorRootfsPath(pid, filepath.Join(ControlDir, hedgeVMFilename)))
+	if err != nil {
+		return "", fmt.Errorf("failed to find the hedge VM of pid %d: %w", pid, err)
+	}
+
+	return strings.TrimSpace(string(name)), nil
+}
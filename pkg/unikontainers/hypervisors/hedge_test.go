@@ -0,0 +1,186 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	hedge "github.com/nubificus/hedge_cli/hedge_api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+// fakeHedge emulates the hedge kernel module. A started VM stays in the
+// list of VMs for the given number of ListVMs calls, or until it gets
+// stopped.
+type fakeHedge struct {
+	mu       sync.Mutex
+	status   error
+	lifetime int
+	vms      map[string]int
+	started  []hedge.VMConfig
+	stopped  []string
+}
+
+func newFakeHedge(lifetime int) *fakeHedge {
+	return &fakeHedge{lifetime: lifetime, vms: make(map[string]int)}
+}
+
+func (f *fakeHedge) Status() error {
+	return f.status
+}
+
+func (f *fakeHedge) StartVM(config hedge.VMConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started = append(f.started, config)
+	f.vms[config.Name] = f.lifetime
+	return nil
+}
+
+func (f *fakeHedge) StopVM(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = append(f.stopped, name)
+	delete(f.vms, name)
+	return nil
+}
+
+func (f *fakeHedge) ListVMs() ([]hedge.VM, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var vms []hedge.VM
+	for name, left := range f.vms {
+		if left == 0 {
+			delete(f.vms, name)
+			continue
+		}
+		f.vms[name] = left - 1
+		vms = append(vms, hedge.VM{ID: len(vms), Name: name})
+	}
+	return vms, nil
+}
+
+// fakeUnikernel returns fixed monitor arguments
+type fakeUnikernel struct {
+	netCli   string
+	blockCli []types.MonitorBlockArgs
+	cli      types.MonitorCliArgs
+}
+
+func (u *fakeUnikernel) Init(types.UnikernelParams) error          { return nil }
+func (u *fakeUnikernel) CommandString() (string, error)            { return "", nil }
+func (u *fakeUnikernel) SupportsBlock() bool                       { return true }
+func (u *fakeUnikernel) SupportsFS(string) bool                    { return false }
+func (u *fakeUnikernel) SupportedMonitors() []string               { return nil }
+func (u *fakeUnikernel) MonitorNetCli(string, string) string       { return u.netCli }
+func (u *fakeUnikernel) MonitorBlockCli() []types.MonitorBlockArgs { return u.blockCli }
+func (u *fakeUnikernel) MonitorCli() types.MonitorCliArgs          { return u.cli }
+
+func TestHedgeVMConfig(t *testing.T) {
+	t.Parallel()
+	args := types.ExecArgs{
+		ContainerID:   "cntr",
+		UnikernelPath: "/unikernel/app.hvt",
+		Command:       "app -- arg",
+		MemSizeB:      512 * 1000 * 1000,
+		Net:           types.NetDevParams{TapDev: "tap0_urunc", MAC: "aa:bb:cc:dd:ee:ff"},
+	}
+
+	t.Run("complete configuration", func(t *testing.T) {
+		t.Parallel()
+		ukernel := &fakeUnikernel{blockCli: []types.MonitorBlockArgs{{ID: "rootfs", Path: "/dev/dm-1"}}}
+		config, err := hedgeVMConfig(args, ukernel)
+		require.NoError(t, err)
+		assert.Equal(t, hedge.VMConfig{
+			Name:    "cntr",
+			Binary:  "/unikernel/app.hvt",
+			Mem:     512,
+			Blk:     "/dev/dm-1",
+			Net:     "tap0_urunc",
+			CmdLine: "app -- arg",
+		}, config)
+	})
+
+	t.Run("unikernel specific network", func(t *testing.T) {
+		t.Parallel()
+		config, err := hedgeVMConfig(args, &fakeUnikernel{netCli: "vif0"})
+		require.NoError(t, err)
+		assert.Equal(t, "vif0", config.Net)
+	})
+
+	t.Run("default memory", func(t *testing.T) {
+		t.Parallel()
+		noMem := args
+		noMem.MemSizeB = 0
+		config, err := hedgeVMConfig(noMem, &fakeUnikernel{})
+		require.NoError(t, err)
+		assert.Equal(t, int(DefaultMemory), config.Mem)
+	})
+
+	t.Run("unsupported configurations", func(t *testing.T) {
+		t.Parallel()
+		withInitrd := args
+		withInitrd.InitrdPath = "/initrd"
+		_, err := hedgeVMConfig(withInitrd, &fakeUnikernel{})
+		assert.Error(t, err)
+
+		_, err = hedgeVMConfig(args, &fakeUnikernel{blockCli: []types.MonitorBlockArgs{{Path: "a"}, {Path: "b"}}})
+		assert.Error(t, err)
+
+		_, err = hedgeVMConfig(args, &fakeUnikernel{blockCli: []types.MonitorBlockArgs{{Path: "a", ReadOnly: true}}})
+		assert.ErrorIs(t, err, ErrReadOnlyBlock)
+
+		withSeparator := args
+		withSeparator.Command = "app|start"
+		_, err = hedgeVMConfig(withSeparator, &fakeUnikernel{})
+		assert.Error(t, err)
+	})
+}
+
+func TestHedgeWaitVM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns when the VM stops", func(t *testing.T) {
+		t.Parallel()
+		fake := newFakeHedge(3)
+		h := &Hedge{ctl: fake}
+		require.NoError(t, fake.StartVM(hedge.VMConfig{Name: "cntr"}))
+		assert.NoError(t, h.waitVM("cntr"))
+		assert.Equal(t, "unknown", h.VMState("cntr"))
+	})
+
+	t.Run("VM never starts", func(t *testing.T) {
+		t.Parallel()
+		h := &Hedge{ctl: newFakeHedge(0)}
+		assert.ErrorIs(t, h.waitVM("cntr"), ErrHedgeVMNotStarted)
+	})
+}
+
+func TestHedgeState(t *testing.T) {
+	t.Parallel()
+	fake := newFakeHedge(-1)
+	h := &Hedge{ctl: fake}
+	assert.NoError(t, h.Ok())
+	require.NoError(t, fake.StartVM(hedge.VMConfig{Name: "cntr"}))
+	assert.Equal(t, "running", h.VMState("cntr"))
+	assert.Equal(t, "unknown", h.VMState("other"))
+
+	fake.status = errors.New("hedge is not loaded")
+	assert.Error(t, h.Ok())
+}
@@ -15,13 +15,16 @@
 package hypervisors
 
 import (
+	"fmt"
 	"os/exec"
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	seccomp "github.com/elastic/go-seccomp-bpf"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -30,8 +33,9 @@ const (
 )
 
 type HVT struct {
-	binaryPath string
-	binary     string
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
 }
 
 // applySeccompFilter applies some secomp filters for the Hvt process.
@@ -120,11 +124,29 @@ func applySeccompFilter() error {
 	return nil
 }
 
-// Stop kills the hvt process
+// Stop sends a SIGTERM to the tender and falls back to SIGKILL if it does
+// not exit within the shutdown timeout.
 func (h *HVT) Stop(pid int) error {
+	return gracefulStop(pid, h.shutdownTimeout, func() error {
+		return syscall.Kill(pid, unix.SIGTERM)
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (h *HVT) Kill(pid int) error {
 	return killProcess(pid)
 }
 
+// Pause freezes the tender process, since solo5 has no control interface
+func (h *HVT) Pause(pid int) error {
+	return freezeProcess(pid)
+}
+
+// Resume thaws a previously frozen tender process
+func (h *HVT) Resume(pid int) error {
+	return thawProcess(pid)
+}
+
 // UsesKVM returns a bool value depending on if the monitor uses KVM
 func (h *HVT) UsesKVM() bool {
 	return true
@@ -135,6 +157,12 @@ func (h *HVT) SupportsSharedfs(_ string) bool {
 	return false
 }
 
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images
+func (h *HVT) SupportsDiskFormat(format string) bool {
+	return format == "raw"
+}
+
 // Path returns the path to the hvt binary.
 func (h *HVT) Path() string {
 	return h.binaryPath
@@ -149,8 +177,25 @@ func (h *HVT) Ok() error {
 }
 
 func (h *HVT) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	cmdString, err := hvtCmdString(h.binaryPath, args, ukernel)
+	if err != nil {
+		return err
+	}
+	cmdArgs := strings.Split(cmdString, " ")
+	if args.Seccomp {
+		err = applySeccompFilter()
+		if err != nil {
+			return err
+		}
+	}
+	vmmLog.WithField("hvt command", cmdString).Debug("Ready to execve hvt")
+	return syscall.Exec(h.binaryPath, cmdArgs, args.Environment) //nolint: gosec
+}
+
+// hvtCmdString builds the command line of the solo5-hvt tender
+func hvtCmdString(binaryPath string, args types.ExecArgs, ukernel types.Unikernel) (string, error) {
 	hvtMem := BytesToStringMB(args.MemSizeB)
-	cmdString := h.binaryPath + " --mem=" + hvtMem
+	cmdString := binaryPath + " --mem=" + hvtMem
 	if args.Net.TapDev != "" {
 		cmdString += " "
 		cmdString += ukernel.MonitorNetCli(args.Net.TapDev, args.Net.MAC)
@@ -158,18 +203,15 @@ func (h *HVT) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
 	extraMonArgs := ukernel.MonitorCli()
 	bArgs := ukernel.MonitorBlockCli()
 	for _, blockArg := range bArgs {
+		if blockArg.ReadOnly {
+			// The Solo5 tenders open every block device read-write
+			return "", fmt.Errorf("%w: hvt can not attach %s read-only", ErrReadOnlyBlock, blockArg.Path)
+		}
 		cmdString = appendNonEmpty(cmdString, " --block:"+blockArg.ID+"=",
 			blockArg.Path)
 	}
 	cmdString = appendNonEmpty(cmdString, " ", extraMonArgs.OtherArgs)
 	cmdString += " " + args.UnikernelPath + " " + args.Command
-	cmdArgs := strings.Split(cmdString, " ")
-	if args.Seccomp {
-		err := applySeccompFilter()
-		if err != nil {
-			return err
-		}
-	}
-	vmmLog.WithField("hvt command", cmdString).Debug("Ready to execve hvt")
-	return syscall.Exec(h.binaryPath, cmdArgs, args.Environment) //nolint: gosec
+
+	return cmdString, nil
 }
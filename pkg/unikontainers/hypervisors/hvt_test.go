@@ -0,0 +1,48 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestHvtCmdString(t *testing.T) {
+	t.Parallel()
+	args := types.ExecArgs{
+		UnikernelPath: "/unikernel/app.hvt",
+		Command:       `{"cmdline":"app"}`,
+		MemSizeB:      512 * 1000 * 1000,
+		Net:           types.NetDevParams{TapDev: "tap0_urunc", MAC: "aa:bb:cc:dd:ee:ff"},
+	}
+	ukernel := &fakeUnikernel{
+		netCli: "--net:tap=tap0_urunc --net-mac:tap=aa:bb:cc:dd:ee:ff",
+		blockCli: []types.MonitorBlockArgs{
+			{ID: "rootfs", Path: "/dev/dm-1"},
+			{ID: "data", Path: "/data.img"},
+		},
+	}
+	cmdString, err := hvtCmdString("/usr/bin/solo5-hvt", args, ukernel)
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/bin/solo5-hvt --mem=512 --net:tap=tap0_urunc --net-mac:tap=aa:bb:cc:dd:ee:ff"+
+		` --block:rootfs=/dev/dm-1 --block:data=/data.img /unikernel/app.hvt {"cmdline":"app"}`, cmdString)
+
+	ukernel.blockCli[1].ReadOnly = true
+	_, err = hvtCmdString("/usr/bin/solo5-hvt", args, ukernel)
+	assert.ErrorIs(t, err, ErrReadOnlyBlock)
+}
@@ -16,27 +16,97 @@ package hypervisors
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors/qmp"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
 )
 
 const (
 	QemuVmm    VmmType = "qemu"
 	QemuBinary string  = "qemu-system-"
+	// QMP socket inside the control directory of the monitor
+	qmpSocketFilename = "qmp.sock"
 )
 
 type Qemu struct {
-	binaryPath string
-	binary     string
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
 }
 
+// Stop requests an ACPI power down of the guest through QMP and falls back
+// to SIGKILL if QEMU does not exit within the shutdown timeout.
 func (q *Qemu) Stop(pid int) error {
+	return gracefulStop(pid, q.shutdownTimeout, func() error {
+		return withQMP(pid, (*qmp.Client).SystemPowerdown)
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (q *Qemu) Kill(pid int) error {
 	return killProcess(pid)
 }
 
+// Pause stops the execution of the guest's vCPUs through QMP
+func (q *Qemu) Pause(pid int) error {
+	return withQMP(pid, (*qmp.Client).Stop)
+}
+
+// Resume continues the execution of the guest's vCPUs through QMP
+func (q *Qemu) Resume(pid int) error {
+	return withQMP(pid, (*qmp.Client).Cont)
+}
+
+// DialVSock connects to the given vsock port of the guest through the
+// vhost-vsock device of the QEMU process
+func (q *Qemu) DialVSock(pid int, port uint32) (io.ReadWriteCloser, error) {
+	cid, err := guestCID(pid, "guest-cid")
+	if err != nil {
+		return nil, err
+	}
+
+	return dialVSock(cid, port)
+}
+
+// Stats returns the statistics that QEMU reports through QMP query-stats
+// for the VM and its vCPUs
+func (q *Qemu) Stats(pid int) (map[string]uint64, error) {
+	counters := make(map[string]uint64)
+	err := withQMP(pid, func(c *qmp.Client) error {
+		for _, target := range []string{"vm", "vcpu"} {
+			results, err := c.QueryStats(target)
+			if err != nil {
+				return err
+			}
+			addQMPStats(counters, target, results)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counters, nil
+}
+
+// withQMP connects to the QMP socket of the QEMU process with the given
+// pid and calls fn with the connected client.
+func withQMP(pid int, fn func(*qmp.Client) error) error {
+	client, err := qmp.Dial(monitorRootfsPath(pid, filepath.Join(ControlDir, qmpSocketFilename)), qmp.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return fn(client)
+}
+
 func (q *Qemu) Ok() error {
 	return nil
 }
@@ -51,6 +121,17 @@ func (q *Qemu) SupportsSharedfs(_ string) bool {
 	return true
 }
 
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images
+func (q *Qemu) SupportsDiskFormat(format string) bool {
+	switch format {
+	case "raw", "qcow2", "vmdk":
+		return true
+	default:
+		return false
+	}
+}
+
 func (q *Qemu) Path() string {
 	return q.binaryPath
 }
@@ -62,6 +143,7 @@ func (q *Qemu) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
 	cmdString += " -cpu host"            // Choose CPU
 	cmdString += " -enable-kvm"          // Enable KVM to use CPU virt extensions
 	cmdString += " -nographic -vga none" // Disable graphic output
+	cmdString += " -qmp unix:" + filepath.Join(ControlDir, qmpSocketFilename) + ",server=on,wait=off"
 
 	if args.VCPUs > 0 {
 		cmdString += fmt.Sprintf(" -smp %d", args.VCPUs)
@@ -105,7 +187,14 @@ func (q *Qemu) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
 		blockCli := blockArg.ExactArgs
 		if blockCli == "" && blockArg.ID != "" && blockArg.Path != "" {
 			blockCli1 := fmt.Sprintf(" -device virtio-blk-pci,serial=%s,drive=%s,scsi=off", blockArg.ID, blockArg.ID)
-			blockCli2 := fmt.Sprintf(" -drive format=raw,if=none,id=%s,file=%s", blockArg.ID, blockArg.Path)
+			format := blockArg.Format
+			if format == "" {
+				format = "raw"
+			}
+			blockCli2 := fmt.Sprintf(" -drive format=%s,if=none,id=%s,file=%s", format, blockArg.ID, blockArg.Path)
+			if blockArg.ReadOnly {
+				blockCli2 += ",readonly=on"
+			}
 			blockCli = blockCli1 + blockCli2
 		}
 		cmdString += blockCli
@@ -116,6 +205,9 @@ func (q *Qemu) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
 	switch args.Sharedfs.Type {
 	case "9pfs":
 		cmdString += " -fsdev local,id=rootfs9p,security_model=none,path=" + args.Sharedfs.Path
+		if args.Sharedfs.ReadOnly {
+			cmdString += ",readonly=on"
+		}
 		cmdString += " -device virtio-9p-pci,fsdev=rootfs9p,mount_tag=fs0"
 	case "virtiofs":
 		cmdString += " -object memory-backend-file,id=mem,size=" + qemuMem + "M,mem-path=/tmp,share=on"
@@ -131,7 +223,7 @@ func (q *Qemu) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
 	}
 	cmdString += extraMonArgs.OtherArgs
 
-	if args.VAccelType == "vsock" {
+	if args.VAccelType == "vsock" || args.AgentVSock {
 		cmdString += " -device vhost-vsock-pci,id=vhost-vsock-pci0,guest-cid=" + fmt.Sprintf("%d", args.VSockDevID)
 	}
 
@@ -0,0 +1,265 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qmp implements a minimal client for the QEMU Machine Protocol.
+// For more details on the protocol, see:
+// https://www.qemu.org/docs/master/interop/qmp-spec.html
+package qmp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const DefaultTimeout = 2 * time.Second
+
+var ErrUnexpectedMessage = errors.New("unexpected QMP message")
+
+// Error is an error response of QMP to a command
+type Error struct {
+	Class string `json:"class"`
+	Desc  string `json:"desc"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("QMP error %s: %s", e.Class, e.Desc)
+}
+
+// Version holds the version of QEMU, as reported in the QMP greeting
+type Version struct {
+	Qemu struct {
+		Major int `json:"major"`
+		Minor int `json:"minor"`
+		Micro int `json:"micro"`
+	} `json:"qemu"`
+	Package string `json:"package"`
+}
+
+// Event is an asynchronous event sent by QMP
+type Event struct {
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Timestamp struct {
+		Seconds      int64 `json:"seconds"`
+		Microseconds int64 `json:"microseconds"`
+	} `json:"timestamp"`
+}
+
+// Status is the run status of the VM, as returned by query-status
+type Status struct {
+	Running bool   `json:"running"`
+	Status  string `json:"status"`
+}
+
+// StatsResult holds the statistics of a provider for a target, as returned
+// by query-stats. QOMPath identifies the vCPU for the "vcpu" target.
+type StatsResult struct {
+	Provider string `json:"provider"`
+	QOMPath  string `json:"qom-path,omitempty"`
+	Stats    []Stat `json:"stats"`
+}
+
+// Stat is a single statistic. Its value is either a number, a boolean or a
+// list of numbers for histograms.
+type Stat struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+type greeting struct {
+	QMP *struct {
+		Version      Version  `json:"version"`
+		Capabilities []string `json:"capabilities"`
+	} `json:"QMP"`
+}
+
+type command struct {
+	Execute   string `json:"execute"`
+	Arguments any    `json:"arguments,omitempty"`
+}
+
+type response struct {
+	Return json.RawMessage `json:"return"`
+	Error  *Error          `json:"error"`
+	Event  string          `json:"event"`
+}
+
+// Client is a connection to a QMP server. A Client is safe for concurrent
+// use, but commands are executed one at a time.
+type Client struct {
+	conn    net.Conn
+	dec     *json.Decoder
+	enc     *json.Encoder
+	timeout time.Duration
+	mu      sync.Mutex
+	// Version is the version of the QEMU instance
+	Version Version
+	// Events holds any asynchronous events received while waiting for
+	// the responses of commands
+	Events []Event
+}
+
+// Dial connects to the QMP unix socket in socketPath and negotiates the
+// capabilities, leaving the connection in command mode. The timeout applies
+// to the connection and to each subsequent command.
+func Dial(socketPath string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to QMP socket %s: %w", socketPath, err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		dec:     json.NewDecoder(conn),
+		enc:     json.NewEncoder(conn),
+		timeout: timeout,
+	}
+	err = c.handshake()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the connection to the QMP server
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) handshake() error {
+	err := c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return err
+	}
+
+	var g greeting
+	err = c.dec.Decode(&g)
+	if err != nil {
+		return fmt.Errorf("failed to read QMP greeting: %w", err)
+	}
+	if g.QMP == nil {
+		return fmt.Errorf("%w: expected greeting", ErrUnexpectedMessage)
+	}
+	c.Version = g.QMP.Version
+
+	return c.execute("qmp_capabilities", nil, nil)
+}
+
+// Execute executes the given command with the given arguments. If result is
+// not nil, the return value of the command gets unmarshalled into it.
+func (c *Client) Execute(cmd string, args any, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return err
+	}
+
+	return c.execute(cmd, args, result)
+}
+
+func (c *Client) execute(cmd string, args any, result any) error {
+	err := c.enc.Encode(command{Execute: cmd, Arguments: args})
+	if err != nil {
+		return fmt.Errorf("failed to send QMP command %s: %w", cmd, err)
+	}
+
+	for {
+		var raw json.RawMessage
+		err = c.dec.Decode(&raw)
+		if err != nil {
+			return fmt.Errorf("failed to read QMP response for %s: %w", cmd, err)
+		}
+		var resp response
+		err = json.Unmarshal(raw, &resp)
+		if err != nil {
+			return err
+		}
+		switch {
+		case resp.Error != nil:
+			return resp.Error
+		case resp.Event != "":
+			var ev Event
+			if err := json.Unmarshal(raw, &ev); err == nil {
+				c.Events = append(c.Events, ev)
+			}
+			continue
+		case resp.Return == nil:
+			return fmt.Errorf("%w: %s", ErrUnexpectedMessage, raw)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Return, result)
+	}
+}
+
+// SystemPowerdown requests an ACPI power down of the guest
+func (c *Client) SystemPowerdown() error {
+	return c.Execute("system_powerdown", nil, nil)
+}
+
+// Stop stops the execution of all the vCPUs of the guest
+func (c *Client) Stop() error {
+	return c.Execute("stop", nil, nil)
+}
+
+// Cont resumes the execution of all the vCPUs of the guest
+func (c *Client) Cont() error {
+	return c.Execute("cont", nil, nil)
+}
+
+// Quit terminates QEMU immediately
+func (c *Client) Quit() error {
+	return c.Execute("quit", nil, nil)
+}
+
+// QueryStatus returns the run status of the VM
+func (c *Client) QueryStatus() (Status, error) {
+	var status Status
+	err := c.Execute("query-status", nil, &status)
+	return status, err
+}
+
+// QueryStats returns the statistics of all providers for the given target
+// (e.g. "vm" or "vcpu")
+func (c *Client) QueryStats(target string) ([]StatsResult, error) {
+	var results []StatsResult
+	err := c.Execute("query-stats", map[string]string{"target": target}, &results)
+	return results, err
+}
+
+// DeviceAdd hot-plugs a device with the given driver, id and properties
+func (c *Client) DeviceAdd(driver string, id string, props map[string]any) error {
+	args := map[string]any{
+		"driver": driver,
+		"id":     id,
+	}
+	for k, v := range props {
+		args[k] = v
+	}
+	return c.Execute("device_add", args, nil)
+}
+
+// DeviceDel requests the hot-unplug of the device with the given id
+func (c *Client) DeviceDel(id string) error {
+	return c.Execute("device_del", map[string]string{"id": id}, nil)
+}
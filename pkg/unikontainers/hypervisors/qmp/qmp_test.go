@@ -0,0 +1,203 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qmp
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGreeting = `{"QMP": {"version": {"qemu": {"micro": 0, "minor": 2, "major": 8}, "package": ""}, "capabilities": ["oob"]}}`
+
+// fakeQMPServer emulates a QEMU QMP server. It answers each command with
+// the matching reply in replies, or with an error for unknown commands.
+// The received commands are sent to the returned channel.
+func fakeQMPServer(t *testing.T, replies map[string]string) (string, <-chan command) {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "qmp.sock")
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan command, 16)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte(testGreeting + "\n"))
+		dec := json.NewDecoder(conn)
+		for {
+			var cmd command
+			if err := dec.Decode(&cmd); err != nil {
+				return
+			}
+			received <- cmd
+			reply, ok := replies[cmd.Execute]
+			switch {
+			case cmd.Execute == "qmp_capabilities":
+				reply = `{"return": {}}`
+			case !ok:
+				reply = `{"error": {"class": "CommandNotFound", "desc": "The command ` + cmd.Execute + ` has not been found"}}`
+			}
+			_, _ = conn.Write([]byte(reply + "\n"))
+		}
+	}()
+
+	return socketPath, received
+}
+
+func TestDial(t *testing.T) {
+	t.Run("negotiates capabilities", func(t *testing.T) {
+		t.Parallel()
+		socketPath, received := fakeQMPServer(t, nil)
+
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+
+		assert.Equal(t, 8, c.Version.Qemu.Major)
+		assert.Equal(t, 2, c.Version.Qemu.Minor)
+		cmd := <-received
+		assert.Equal(t, "qmp_capabilities", cmd.Execute)
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		t.Parallel()
+		_, err := Dial(filepath.Join(t.TempDir(), "missing.sock"), DefaultTimeout)
+		assert.Error(t, err)
+	})
+
+	t.Run("no greeting", func(t *testing.T) {
+		t.Parallel()
+		socketPath := filepath.Join(t.TempDir(), "qmp.sock")
+		l, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		defer l.Close()
+		go func() {
+			conn, err := l.Accept()
+			if err == nil {
+				defer conn.Close()
+				time.Sleep(time.Second)
+			}
+		}()
+
+		_, err = Dial(socketPath, 100*time.Millisecond)
+		assert.ErrorContains(t, err, "greeting")
+	})
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("commands without return value", func(t *testing.T) {
+		t.Parallel()
+		socketPath, received := fakeQMPServer(t, map[string]string{
+			"system_powerdown": `{"return": {}}`,
+			"stop":             `{"return": {}}`,
+			"cont":             `{"return": {}}`,
+		})
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+		<-received
+
+		assert.NoError(t, c.SystemPowerdown())
+		assert.Equal(t, "system_powerdown", (<-received).Execute)
+		assert.NoError(t, c.Stop())
+		assert.Equal(t, "stop", (<-received).Execute)
+		assert.NoError(t, c.Cont())
+		assert.Equal(t, "cont", (<-received).Execute)
+	})
+
+	t.Run("events before the response are skipped", func(t *testing.T) {
+		t.Parallel()
+		socketPath, _ := fakeQMPServer(t, map[string]string{
+			"query-status": `{"event": "STOP", "timestamp": {"seconds": 1, "microseconds": 2}}` + "\n" +
+				`{"return": {"running": false, "singlestep": false, "status": "paused"}}`,
+		})
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+
+		status, err := c.QueryStatus()
+		assert.NoError(t, err)
+		assert.False(t, status.Running)
+		assert.Equal(t, "paused", status.Status)
+		require.Len(t, c.Events, 1)
+		assert.Equal(t, "STOP", c.Events[0].Event)
+	})
+
+	t.Run("query stats", func(t *testing.T) {
+		t.Parallel()
+		socketPath, received := fakeQMPServer(t, map[string]string{
+			"query-stats": `{"return": [{"provider": "kvm", "qom-path": "/machine/unattached/device[0]", ` +
+				`"stats": [{"name": "exits", "value": 42}, {"name": "halt_poll_success_hist", "value": [1, 2]}]}]}`,
+		})
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+		<-received
+
+		results, err := c.QueryStats("vcpu")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"target": "vcpu"}, (<-received).Arguments)
+		require.Len(t, results, 1)
+		assert.Equal(t, "kvm", results[0].Provider)
+		assert.Equal(t, "/machine/unattached/device[0]", results[0].QOMPath)
+		require.Len(t, results[0].Stats, 2)
+		assert.Equal(t, "exits", results[0].Stats[0].Name)
+		assert.JSONEq(t, "42", string(results[0].Stats[0].Value))
+	})
+
+	t.Run("command with arguments", func(t *testing.T) {
+		t.Parallel()
+		socketPath, received := fakeQMPServer(t, map[string]string{
+			"device_add": `{"return": {}}`,
+		})
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+		<-received
+
+		err = c.DeviceAdd("virtio-blk-pci", "vol1", map[string]any{"drive": "drive1"})
+		assert.NoError(t, err)
+		cmd := <-received
+		assert.Equal(t, "device_add", cmd.Execute)
+		assert.Equal(t, map[string]any{
+			"driver": "virtio-blk-pci",
+			"id":     "vol1",
+			"drive":  "drive1",
+		}, cmd.Arguments)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		t.Parallel()
+		socketPath, _ := fakeQMPServer(t, nil)
+		c, err := Dial(socketPath, DefaultTimeout)
+		require.NoError(t, err)
+		defer c.Close()
+
+		err = c.Quit()
+		var qmpErr *Error
+		require.ErrorAs(t, err, &qmpErr)
+		assert.Equal(t, "CommandNotFound", qmpErr.Class)
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"runtime"
+	"strings"
+	"syscall"
+
+	seccomp "github.com/elastic/go-seccomp-bpf"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
+)
+
+const SandboxVmm VmmType = "sandbox"
+
+// Sandbox runs unikernels built for spt as plain processes, on hosts
+// without hardware-assisted virtualization. It uses the solo5-spt tender,
+// but confines it with a seccomp filter, which is stricter than the one of
+// the tender itself and, contrary to spt, is always enforced. The filter
+// gets loaded before the execution of the tender and therefore it covers
+// its whole lifetime and not only the execution of the guest.
+type Sandbox struct {
+	SPT
+}
+
+func (s *Sandbox) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	cmdString, err := sptCmdString(s.binaryPath, args, ukernel)
+	if err != nil {
+		return err
+	}
+	cmdArgs := strings.Split(cmdString, " ")
+
+	filter := sandboxSeccompFilter()
+	err = seccomp.LoadFilter(filter)
+	if err != nil {
+		vmmLog.Error("Could not load sandbox seccomp filters")
+		return err
+	}
+	vmmLog.WithField("allowed syscalls", filter.Policy.Syscalls).Debug("Loaded sandbox seccomp filters")
+
+	vmmLog.WithField("sandbox command", cmdString).Debug("Ready to execve sandbox")
+	return syscall.Exec(s.binaryPath, cmdArgs, args.Environment) //nolint: gosec
+}
+
+// sandboxSeccompFilter returns the seccomp filter of the sandbox. It allows
+// only the system calls that the Go runtime needs until the execution of
+// the tender and the ones that the tender needs to load the unikernel and
+// serve its network and block devices. In comparison with the filter of
+// hvt, it does not allow sending or receiving through sockets, it restricts
+// ioctl to the configuration of the tap device and it kills the whole
+// process on any other system call.
+func sandboxSeccompFilter() seccomp.Filter {
+	syscalls := []string{
+		// Execution of the tender
+		"execve",
+		"brk",
+		"mmap",
+		"munmap",
+		"mprotect",
+		"madvise",
+		"set_tid_address",
+		"set_robust_list",
+		"rseq",
+		"prlimit64",
+		"getrandom",
+		// Loading the unikernel and opening the block and tap devices
+		"openat",
+		"read",
+		"pread64",
+		"lseek",
+		"fstat",
+		"close",
+		"personality",
+		// Looking up the index of the tap device (if_nametoindex)
+		"socket",
+		// Serving the guest
+		"write",
+		"pwrite64",
+		"epoll_create1",
+		"epoll_ctl",
+		"epoll_pwait",
+		"timerfd_create",
+		"timerfd_settime",
+		"clock_gettime",
+		// The tender applies its own seccomp filter before starting the guest
+		"prctl",
+		"seccomp",
+		// The Go runtime, until the tender gets executed
+		"futex",
+		"nanosleep",
+		"sched_yield",
+		"gettid",
+		"getpid",
+		"tgkill",
+		"rt_sigaction",
+		"rt_sigprocmask",
+		"rt_sigreturn",
+		"sigaltstack",
+		"exit",
+		"exit_group",
+	}
+
+	if runtime.GOARCH == "arm64" {
+		syscalls = append(syscalls, "faccessat", "fstatat")
+	} else {
+		syscalls = append(syscalls, "open", "stat", "access", "arch_prctl", "newfstatat")
+	}
+
+	return seccomp.Filter{
+		// Set the threads no_new_privs bit, disabling any new child or execve
+		// system call to grant privileges that the parent does not have.
+		NoNewPrivs: true,
+		// Sync the filter to all threads created by the Go runtime.
+		Flag: seccomp.FilterFlagTSync,
+		Policy: seccomp.Policy{
+			DefaultAction: seccomp.ActionKillProcess,
+			Syscalls: []seccomp.SyscallGroup{
+				{
+					Action: seccomp.ActionAllow,
+					Names:  syscalls,
+				},
+				{
+					// Attaching the tap device, looking up its index
+					// and checking if the console is a terminal
+					Action: seccomp.ActionAllow,
+					NamesWithCondtions: []seccomp.NameWithConditions{
+						{
+							Name: "ioctl",
+							Conditions: []seccomp.Condition{
+								{Argument: 1, Operation: seccomp.Equal, Value: unix.TUNSETIFF},
+							},
+						},
+						{
+							Name: "ioctl",
+							Conditions: []seccomp.Condition{
+								{Argument: 1, Operation: seccomp.Equal, Value: unix.SIOCGIFINDEX},
+							},
+						},
+						{
+							Name: "ioctl",
+							Conditions: []seccomp.Condition{
+								{Argument: 1, Operation: seccomp.Equal, Value: unix.TCGETS},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
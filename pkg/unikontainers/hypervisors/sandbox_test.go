@@ -0,0 +1,169 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+
+	seccomp "github.com/elastic/go-seccomp-bpf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
+)
+
+// sandboxHelperEnv makes the test binary run as a helper, which loads the
+// seccomp filter of the sandbox and then performs the given action.
+const sandboxHelperEnv = "URUNC_TEST_SANDBOX_HELPER"
+
+func TestMain(m *testing.M) {
+	if action, ok := os.LookupEnv(sandboxHelperEnv); ok {
+		os.Exit(sandboxHelper(action))
+	}
+	os.Exit(m.Run())
+}
+
+// sandboxHelper loads the seccomp filter of the sandbox and performs the
+// given action, like the tender would do. The helper gets killed if the
+// filter does not allow any of the system calls of the action.
+func sandboxHelper(action string) int {
+	ifr, err := unix.NewIfreq("tapurunctest0")
+	if err != nil {
+		return 2
+	}
+	ifr.SetUint16(unix.IFF_TAP | unix.IFF_NO_PI)
+
+	err = seccomp.LoadFilter(sandboxSeccompFilter())
+	if err != nil {
+		return 2
+	}
+
+	switch action {
+	case "tap":
+		// Attach the tap device and look up its index, like Solo5 does
+		tapFd, err := unix.Open("/dev/net/tun", unix.O_RDWR|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return 3
+		}
+		defer unix.Close(tapFd)
+		err = unix.IoctlIfreq(tapFd, unix.TUNSETIFF, ifr)
+		if err != nil {
+			return 3
+		}
+		sockFd, err := unix.Socket(unix.AF_UNIX, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return 3
+		}
+		defer unix.Close(sockFd)
+		err = unix.IoctlIfreq(sockFd, unix.SIOCGIFINDEX, ifr)
+		if err != nil {
+			return 3
+		}
+	case "forbidden":
+		unix.Getuid()
+	}
+
+	return 0
+}
+
+// runSandboxHelper runs the test binary as a helper for the given action.
+func runSandboxHelper(t *testing.T, action string) *os.ProcessState {
+	cmd := exec.Command(os.Args[0]) //nolint: gosec
+	cmd.Env = append(os.Environ(), sandboxHelperEnv+"="+action)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		require.NoError(t, err)
+	}
+
+	return cmd.ProcessState
+}
+
+func TestSandboxSeccompFilter(t *testing.T) {
+	t.Parallel()
+	filter := sandboxSeccompFilter()
+	assert.True(t, filter.NoNewPrivs)
+	assert.Equal(t, seccomp.ActionKillProcess, filter.Policy.DefaultAction)
+
+	_, err := filter.Policy.Assemble()
+	require.NoError(t, err)
+
+	for _, group := range filter.Policy.Syscalls {
+		for _, name := range []string{"connect", "bind", "getsockname", "sendto", "recvmsg", "ioctl", "clone", "ptrace"} {
+			assert.NotContains(t, group.Names, name)
+		}
+		for _, cond := range group.NamesWithCondtions {
+			assert.Equal(t, "ioctl", cond.Name)
+		}
+	}
+}
+
+func TestSandboxSeccompFilterAttachTap(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() != 0 {
+		t.Skip("attaching a tap device requires root")
+	}
+	_, err := os.Stat("/dev/net/tun")
+	if err != nil {
+		t.Skip("/dev/net/tun is not available")
+	}
+
+	state := runSandboxHelper(t, "tap")
+	assert.True(t, state.Success(), "sandbox helper failed: %s", state)
+}
+
+func TestSandboxSeccompFilterKillsProcess(t *testing.T) {
+	t.Parallel()
+	state := runSandboxHelper(t, "forbidden")
+	status, ok := state.Sys().(syscall.WaitStatus)
+	require.True(t, ok)
+	assert.True(t, status.Signaled())
+	assert.Equal(t, syscall.SIGSYS, status.Signal())
+}
+
+func TestSandboxVMM(t *testing.T) {
+	t.Parallel()
+	var vmm types.VMM = &Sandbox{SPT: SPT{binaryPath: "/usr/bin/solo5-spt"}}
+	assert.False(t, vmm.UsesKVM())
+	assert.False(t, vmm.SupportsSharedfs("virtio"))
+	assert.Equal(t, "/usr/bin/solo5-spt", vmm.Path())
+	assert.Implements(t, (*types.VMMPauser)(nil), vmm)
+}
+
+func TestSptCmdString(t *testing.T) {
+	t.Parallel()
+	args := types.ExecArgs{
+		UnikernelPath: "/unikernel/app.spt",
+		Command:       `{"cmdline":"app"}`,
+		MemSizeB:      512 * 1000 * 1000,
+		Net:           types.NetDevParams{TapDev: "tap0_urunc", MAC: "aa:bb:cc:dd:ee:ff"},
+	}
+	ukernel := &fakeUnikernel{
+		netCli:   "--net:tap=tap0_urunc --net-mac:tap=aa:bb:cc:dd:ee:ff",
+		blockCli: []types.MonitorBlockArgs{{ID: "rootfs", Path: "/dev/dm-1"}},
+	}
+	cmdString, err := sptCmdString("/usr/bin/solo5-spt", args, ukernel)
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/bin/solo5-spt --mem=512 --net:tap=tap0_urunc --net-mac:tap=aa:bb:cc:dd:ee:ff"+
+		` --block:rootfs=/dev/dm-1 /unikernel/app.spt {"cmdline":"app"}`, cmdString)
+
+	ukernel.blockCli = []types.MonitorBlockArgs{{ID: "rootfs", Path: "/dev/dm-1", ReadOnly: true}}
+	_, err = sptCmdString("/usr/bin/solo5-spt", args, ukernel)
+	assert.ErrorIs(t, err, ErrReadOnlyBlock)
+}
@@ -15,11 +15,14 @@
 package hypervisors
 
 import (
+	"fmt"
 	"os/exec"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -28,15 +31,34 @@ const (
 )
 
 type SPT struct {
-	binaryPath string
-	binary     string
+	binaryPath      string
+	binary          string
+	shutdownTimeout time.Duration
 }
 
-// Stop kills the spt process
+// Stop sends a SIGTERM to the tender and falls back to SIGKILL if it does
+// not exit within the shutdown timeout.
 func (s *SPT) Stop(pid int) error {
+	return gracefulStop(pid, s.shutdownTimeout, func() error {
+		return syscall.Kill(pid, unix.SIGTERM)
+	})
+}
+
+// Kill terminates the monitor process immediately
+func (s *SPT) Kill(pid int) error {
 	return killProcess(pid)
 }
 
+// Pause freezes the tender process, since solo5 has no control interface
+func (s *SPT) Pause(pid int) error {
+	return freezeProcess(pid)
+}
+
+// Resume thaws a previously frozen tender process
+func (s *SPT) Resume(pid int) error {
+	return thawProcess(pid)
+}
+
 // UsesKVM returns a bool value depending on if the monitor uses KVM
 func (s *SPT) UsesKVM() bool {
 	return false
@@ -47,6 +69,12 @@ func (s *SPT) SupportsSharedfs(_ string) bool {
 	return false
 }
 
+// SupportsDiskFormat returns a bool value depending on the monitor support
+// for the given format of disk images
+func (s *SPT) SupportsDiskFormat(format string) bool {
+	return format == "raw"
+}
+
 // Path returns the path to the spt binary.
 func (s *SPT) Path() string {
 	return s.binaryPath
@@ -61,21 +89,35 @@ func (s *SPT) Ok() error {
 }
 
 func (s *SPT) Execve(args types.ExecArgs, ukernel types.Unikernel) error {
+	cmdString, err := sptCmdString(s.binaryPath, args, ukernel)
+	if err != nil {
+		return err
+	}
+	cmdArgs := strings.Split(cmdString, " ")
+	vmmLog.WithField("spt command", cmdString).Debug("Ready to execve spt")
+	return syscall.Exec(s.binaryPath, cmdArgs, args.Environment) //nolint: gosec
+}
+
+// sptCmdString builds the command line of the solo5-spt tender
+func sptCmdString(binaryPath string, args types.ExecArgs, ukernel types.Unikernel) (string, error) {
 	sptMem := BytesToStringMB(args.MemSizeB)
-	cmdString := s.binaryPath + " --mem=" + sptMem
+	cmdString := binaryPath + " --mem=" + sptMem
 	if args.Net.TapDev != "" {
 		cmdString += " "
 		cmdString += ukernel.MonitorNetCli(args.Net.TapDev, args.Net.MAC)
 	}
 	bArgs := ukernel.MonitorBlockCli()
 	for _, blockArg := range bArgs {
+		if blockArg.ReadOnly {
+			// The Solo5 tenders open every block device read-write
+			return "", fmt.Errorf("%w: spt can not attach %s read-only", ErrReadOnlyBlock, blockArg.Path)
+		}
 		cmdString = appendNonEmpty(cmdString, " --block:"+blockArg.ID+"=",
 			blockArg.Path)
 	}
 	extraMonArgs := ukernel.MonitorCli()
 	cmdString = appendNonEmpty(cmdString, " ", extraMonArgs.OtherArgs)
 	cmdString += " " + args.UnikernelPath + " " + args.Command
-	cmdArgs := strings.Split(cmdString, " ")
-	vmmLog.WithField("spt command", cmdString).Debug("Ready to execve spt")
-	return syscall.Exec(s.binaryPath, cmdArgs, args.Environment) //nolint: gosec
+
+	return cmdString, nil
 }
@@ -0,0 +1,185 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors/qmp"
+	"golang.org/x/sys/unix"
+)
+
+// fcMetricsGroups are the groups of the Firecracker metrics which get
+// reported as guest statistics.
+var fcMetricsGroups = []string{"net", "block", "vcpu"}
+
+// addQMPStats adds the numeric statistics of the query-stats results to
+// counters, using "<target>.<provider>.<name>" as the key. Statistics of
+// the same name are summed up, e.g. the ones of each vCPU.
+func addQMPStats(counters map[string]uint64, target string, results []qmp.StatsResult) {
+	for _, result := range results {
+		for _, stat := range result.Stats {
+			var value uint64
+			if json.Unmarshal(stat.Value, &value) != nil {
+				// Skip booleans and histograms
+				continue
+			}
+			counters[target+"."+result.Provider+"."+stat.Name] += value
+		}
+	}
+}
+
+// sumFirecrackerMetrics parses the metrics that Firecracker has written in
+// r and returns the counters of fcMetricsGroups, using "<group>.<name>" as
+// the key. Firecracker writes a JSON object per line on every flush and each
+// counter holds the increment since the previous flush. Therefore, the
+// counters get summed up across all lines.
+func sumFirecrackerMetrics(r io.Reader) (map[string]uint64, error) {
+	counters := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var metrics map[string]json.RawMessage
+		err := json.Unmarshal([]byte(line), &metrics)
+		if err != nil {
+			return nil, err
+		}
+		for name, data := range metrics {
+			if !isFirecrackerMetricsGroup(name) {
+				continue
+			}
+			var group map[string]json.RawMessage
+			if json.Unmarshal(data, &group) != nil {
+				continue
+			}
+			for metric, value := range group {
+				var count uint64
+				if json.Unmarshal(value, &count) != nil {
+					// Skip latency aggregates
+					continue
+				}
+				counters[name+"."+metric] += count
+			}
+		}
+	}
+
+	return counters, scanner.Err()
+}
+
+// fcMetricsState is the state of the Firecracker metrics that urunc keeps
+// across the calls of Stats. Totals holds the sum of the metrics up to
+// Offset in the metrics file.
+type fcMetricsState struct {
+	Offset int64             `json:"offset"`
+	Totals map[string]uint64 `json:"totals"`
+}
+
+// collectFirecrackerMetrics adds the metrics that Firecracker has written
+// since the previous call to the totals in the state file and returns the
+// new totals. Firecracker does not open the metrics file in append mode and
+// hence the file can not get truncated. Instead, only the new metrics get
+// read and the disk space of the summed up metrics gets freed.
+func collectFirecrackerMetrics(metricsPath string, statePath string) (map[string]uint64, error) {
+	stateFile, err := os.OpenFile(statePath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer stateFile.Close()
+	// Concurrent calls would sum up the same metrics twice
+	err = unix.Flock(int(stateFile.Fd()), unix.LOCK_EX)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", statePath, err)
+	}
+
+	var state fcMetricsState
+	data, err := io.ReadAll(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &state)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Firecracker metrics state: %w", err)
+		}
+	}
+	if state.Totals == nil {
+		state.Totals = make(map[string]uint64)
+	}
+
+	metricsFile, err := os.OpenFile(metricsPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer metricsFile.Close()
+	metrics, err := io.ReadAll(io.NewSectionReader(metricsFile, state.Offset, math.MaxInt64-state.Offset))
+	if err != nil {
+		return nil, err
+	}
+	// Skip the last line, if Firecracker has not finished writing it
+	metrics = metrics[:bytes.LastIndexByte(metrics, '\n')+1]
+	counters, err := sumFirecrackerMetrics(bytes.NewReader(metrics))
+	if err != nil {
+		return nil, err
+	}
+	for name, count := range counters {
+		state.Totals[name] += count
+	}
+	state.Offset += int64(len(metrics))
+
+	if state.Offset > 0 {
+		err = unix.Fallocate(int(metricsFile.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, 0, state.Offset)
+		if err != nil {
+			vmmLog.WithError(err).Debug("failed to free the disk space of the Firecracker metrics")
+		}
+	}
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	err = stateFile.Truncate(0)
+	if err != nil {
+		return nil, err
+	}
+	_, err = stateFile.WriteAt(data, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Totals, nil
+}
+
+// isFirecrackerMetricsGroup returns true if the metrics group with the
+// given name, e.g. "net" or the per device "net_eth0", should be reported.
+func isFirecrackerMetricsGroup(name string) bool {
+	for _, group := range fcMetricsGroups {
+		if name == group || strings.HasPrefix(name, group+"_") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors/qmp"
+)
+
+func TestSumFirecrackerMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counters are summed across flushes", func(t *testing.T) {
+		t.Parallel()
+		metrics := `{"utc_timestamp_ms": 1, "net": {"rx_bytes_count": 100, "tx_bytes_count": 10}, "api_server": {"process_startup_time_us": 5}}
+{"utc_timestamp_ms": 2, "net": {"rx_bytes_count": 50, "tx_bytes_count": 0}, "net_eth0": {"rx_bytes_count": 50}, "block": {"read_bytes": 4096, "read_agg": {"min_us": 1, "max_us": 2, "sum_us": 3}}}
+
+`
+		counters, err := sumFirecrackerMetrics(strings.NewReader(metrics))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]uint64{
+			"net.rx_bytes_count":      150,
+			"net.tx_bytes_count":      10,
+			"net_eth0.rx_bytes_count": 50,
+			"block.read_bytes":        4096,
+		}, counters)
+	})
+
+	t.Run("empty metrics file", func(t *testing.T) {
+		t.Parallel()
+		counters, err := sumFirecrackerMetrics(strings.NewReader(""))
+		require.NoError(t, err)
+		assert.Empty(t, counters)
+	})
+
+	t.Run("invalid metrics", func(t *testing.T) {
+		t.Parallel()
+		_, err := sumFirecrackerMetrics(strings.NewReader("{\"net\": "))
+		assert.Error(t, err)
+	})
+}
+
+func TestCollectFirecrackerMetrics(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	metricsPath := filepath.Join(dir, FCMetricsFilename)
+	statePath := filepath.Join(dir, FCMetricsStateFilename)
+	appendMetrics := func(metrics string) {
+		f, err := os.OpenFile(metricsPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		require.NoError(t, err)
+		_, err = f.WriteString(metrics)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	appendMetrics(`{"net": {"rx_bytes_count": 100}}` + "\n" + `{"net": {"rx_bytes_count": 50}}` + "\n")
+	counters, err := collectFirecrackerMetrics(metricsPath, statePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"net.rx_bytes_count": 150}, counters)
+
+	// Only the new metrics get summed up and an incomplete line waits for
+	// the next call.
+	appendMetrics(`{"net": {"rx_bytes_count": 10}}` + "\n" + `{"net": {"rx_bytes_`)
+	counters, err = collectFirecrackerMetrics(metricsPath, statePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"net.rx_bytes_count": 160}, counters)
+
+	appendMetrics(`count": 5}}` + "\n")
+	counters, err = collectFirecrackerMetrics(metricsPath, statePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"net.rx_bytes_count": 165}, counters)
+
+	// Without new metrics, the totals stay the same
+	counters, err = collectFirecrackerMetrics(metricsPath, statePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"net.rx_bytes_count": 165}, counters)
+
+	_, err = collectFirecrackerMetrics(filepath.Join(dir, "missing"), filepath.Join(dir, "state"))
+	assert.Error(t, err)
+}
+
+func TestAddQMPStats(t *testing.T) {
+	t.Parallel()
+	results := []qmp.StatsResult{
+		{Provider: "kvm", QOMPath: "/machine/unattached/device[0]", Stats: []qmp.Stat{
+			{Name: "exits", Value: json.RawMessage("10")},
+			{Name: "guest_mode", Value: json.RawMessage("false")},
+			{Name: "halt_poll_success_hist", Value: json.RawMessage("[1, 2]")},
+		}},
+		{Provider: "kvm", QOMPath: "/machine/unattached/device[1]", Stats: []qmp.Stat{
+			{Name: "exits", Value: json.RawMessage("5")},
+		}},
+	}
+
+	counters := make(map[string]uint64)
+	addQMPStats(counters, "vcpu", results)
+	assert.Equal(t, map[string]uint64{"vcpu.kvm.exits": 15}, counters)
+}
@@ -17,14 +17,19 @@ package hypervisors
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"golang.org/x/sys/unix"
 )
 
+const cgroupRoot = "/sys/fs/cgroup"
+
 func cpuArch() string {
 	switch runtime.GOARCH {
 	case "arm64":
@@ -73,15 +78,22 @@ func killProcess(pid int) error {
 	if err != nil {
 		return err
 	}
+
+	return waitProcessExit(pid, timeout)
+}
+
+// waitProcessExit polls the process with the given pid until it exits or
+// the timeout expires.
+func waitProcessExit(pid int, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	for {
-		if err := syscall.Kill(pid, 0); err != nil {
-			if errors.Is(err, syscall.ESRCH) {
-				// process is dead
-				break
-			}
+		exited, err := processExited(pid)
+		if err != nil {
 			return fmt.Errorf("error checking if process with pid %d is alive: %w", pid, err)
 		}
+		if exited {
+			break
+		}
 		if time.Now().After(deadline) {
 			return fmt.Errorf("timeout waiting for pid %d to die", pid)
 		}
@@ -90,3 +102,126 @@ func killProcess(pid int) error {
 
 	return nil
 }
+
+// processExited returns true if the process with the given pid is gone or
+// is a zombie. The monitor is not a child of urunc, so it stays a zombie
+// until its parent reaps it, which kill(pid, 0) does not tell apart from a
+// running process.
+func processExited(pid int) (bool, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ESRCH) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	// The state follows the command name, which is in parentheses and
+	// can contain any character.
+	var fields []string
+	if i := strings.LastIndexByte(string(data), ')'); i >= 0 {
+		fields = strings.Fields(string(data[i+1:]))
+	}
+	if len(fields) == 0 {
+		return false, fmt.Errorf("malformed stat of pid %d", pid)
+	}
+
+	return fields[0] == "Z" || fields[0] == "X", nil
+}
+
+// gracefulStop asks the guest to power off using powerOff and waits for
+// the monitor process to exit. If the request fails or the monitor does not
+// exit within the timeout, the monitor process gets killed.
+func gracefulStop(pid int, timeout time.Duration, powerOff func() error) error {
+	err := powerOff()
+	if err != nil {
+		vmmLog.WithError(err).Warnf("failed to gracefully stop pid %d, killing it", pid)
+		return killProcess(pid)
+	}
+
+	err = waitProcessExit(pid, timeout)
+	if err != nil {
+		vmmLog.WithError(err).Warnf("pid %d did not exit gracefully, killing it", pid)
+		return killProcess(pid)
+	}
+
+	return nil
+}
+
+// monitorRootfsPath returns the host path of a file inside the rootfs of the
+// monitor process with the given pid.
+func monitorRootfsPath(pid int, path string) string {
+	return filepath.Join("/proc", strconv.Itoa(pid), "root", path)
+}
+
+// ProcessCgroup returns the host path of the cgroup v2 of the process with
+// the given pid.
+func ProcessCgroup(pid int) (string, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return filepath.Join(cgroupRoot, path), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find the cgroup v2 of pid %d", pid)
+}
+
+// exclusiveCgroup returns the cgroup v2 of the process with the given pid,
+// only if the process is the sole member of the cgroup and the cgroup has no
+// child cgroups. Otherwise, freezing the cgroup would affect other processes.
+func exclusiveCgroup(pid int) (string, bool) {
+	cgroup, err := ProcessCgroup(pid)
+	if err != nil || cgroup == cgroupRoot {
+		return "", false
+	}
+	procs, err := os.ReadFile(filepath.Join(cgroup, "cgroup.procs"))
+	if err != nil || strings.TrimSpace(string(procs)) != strconv.Itoa(pid) {
+		return "", false
+	}
+	entries, err := os.ReadDir(cgroup)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return "", false
+		}
+	}
+
+	return cgroup, true
+}
+
+// freezeProcess freezes the process with the given pid using the cgroup v2
+// freezer, if the process has its own cgroup. Otherwise, it falls back to
+// SIGSTOP.
+func freezeProcess(pid int) error {
+	if cgroup, ok := exclusiveCgroup(pid); ok {
+		err := os.WriteFile(filepath.Join(cgroup, "cgroup.freeze"), []byte("1"), 0o644) //nolint: gosec
+		if err == nil {
+			return nil
+		}
+		vmmLog.WithError(err).Warnf("failed to freeze cgroup of pid %d, falling back to SIGSTOP", pid)
+	}
+
+	return syscall.Kill(pid, unix.SIGSTOP)
+}
+
+// thawProcess resumes a process which was frozen with freezeProcess
+func thawProcess(pid int) error {
+	if cgroup, ok := exclusiveCgroup(pid); ok {
+		freezeFile := filepath.Join(cgroup, "cgroup.freeze")
+		state, err := os.ReadFile(freezeFile)
+		if err == nil && strings.TrimSpace(string(state)) == "1" {
+			err = os.WriteFile(freezeFile, []byte("0"), 0o644) //nolint: gosec
+			if err != nil {
+				return fmt.Errorf("failed to thaw cgroup of pid %d: %w", pid, err)
+			}
+		}
+	}
+
+	return syscall.Kill(pid, unix.SIGCONT)
+}
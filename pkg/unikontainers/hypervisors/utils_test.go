@@ -0,0 +1,58 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitProcessExit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("running process", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command("sleep", "10")
+		require.NoError(t, cmd.Start())
+		defer func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}()
+
+		assert.Error(t, waitProcessExit(cmd.Process.Pid, 200*time.Millisecond))
+	})
+
+	t.Run("zombie process", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command("true")
+		require.NoError(t, cmd.Start())
+		// Not reaped until the end of the test, so it stays a zombie
+		defer func() { _ = cmd.Wait() }()
+
+		assert.NoError(t, waitProcessExit(cmd.Process.Pid, 2*time.Second))
+	})
+
+	t.Run("reaped process", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command("true")
+		require.NoError(t, cmd.Run())
+
+		assert.NoError(t, waitProcessExit(cmd.Process.Pid, 0))
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
@@ -25,32 +26,71 @@ import (
 
 const DefaultMemory uint64 = 256 // The default memory for every hypervisor: 256 MB
 
+// DefaultShutdownTimeout is the time to wait for a guest to power off before
+// killing the monitor, if not specified in the monitor's configuration.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ControlDir is the directory inside the rootfs of the monitor, where the
+// monitors create their control sockets. It is a bind mount of a directory
+// under the base dir of the container.
+const ControlDir = "/tmp/urunc"
+
 type VmmType string
 
 var ErrVMMNotInstalled = errors.New("vmm not found")
+
+// ErrReadOnlyBlock is returned by the monitors which can not attach a block
+// device read-only.
+var ErrReadOnlyBlock = errors.New("read-only block devices are not supported")
 var vmmLog = logrus.WithField("subsystem", "monitors")
 
 type VMMFactory struct {
 	binary     string
-	createFunc func(binary, binaryPath string) types.VMM
+	createFunc func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM
 }
 
 var vmmFactories = map[VmmType]VMMFactory{
 	SptVmm: {
-		binary:     SptBinary,
-		createFunc: func(binary, binaryPath string) types.VMM { return &SPT{binary: binary, binaryPath: binaryPath} },
+		binary: SptBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &SPT{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
+	},
+	SandboxVmm: {
+		binary: SptBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &Sandbox{SPT: SPT{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}}
+		},
 	},
 	HvtVmm: {
-		binary:     HvtBinary,
-		createFunc: func(binary, binaryPath string) types.VMM { return &HVT{binary: binary, binaryPath: binaryPath} },
+		binary: HvtBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &HVT{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
 	},
 	QemuVmm: {
-		binary:     QemuBinary,
-		createFunc: func(binary, binaryPath string) types.VMM { return &Qemu{binary: binary, binaryPath: binaryPath} },
+		binary: QemuBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &Qemu{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
 	},
 	FirecrackerVmm: {
-		binary:     FirecrackerBinary,
-		createFunc: func(binary, binaryPath string) types.VMM { return &Firecracker{binary: binary, binaryPath: binaryPath} },
+		binary: FirecrackerBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &Firecracker{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
+	},
+	CrosvmVmm: {
+		binary: CrosvmBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &Crosvm{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
+	},
+	CloudHypervisorVmm: {
+		binary: CloudHypervisorBinary,
+		createFunc: func(binary, binaryPath string, shutdownTimeout time.Duration) types.VMM {
+			return &CloudHypervisor{binary: binary, binaryPath: binaryPath, shutdownTimeout: shutdownTimeout}
+		},
 	},
 }
 
@@ -63,7 +103,7 @@ func NewVMM(vmmType VmmType, monitors map[string]types.MonitorConfig) (vmm types
 
 	// Handle Hedge separately since it is not in vmmFactories
 	if vmmType == HedgeVmm {
-		hedge := Hedge{}
+		hedge := Hedge{shutdownTimeout: getShutdownTimeout(vmmType, monitors)}
 		if err := hedge.Ok(); err != nil {
 			return nil, ErrVMMNotInstalled
 		}
@@ -80,7 +120,15 @@ func NewVMM(vmmType VmmType, monitors map[string]types.MonitorConfig) (vmm types
 		return nil, err
 	}
 
-	return factory.createFunc(factory.binary, vmmPath), nil
+	return factory.createFunc(factory.binary, vmmPath, getShutdownTimeout(vmmType, monitors)), nil
+}
+
+func getShutdownTimeout(vmmType VmmType, monitors map[string]types.MonitorConfig) time.Duration {
+	if timeout := monitors[string(vmmType)].ShutdownTimeout; timeout != 0 {
+		return time.Duration(timeout) * time.Second
+	}
+
+	return DefaultShutdownTimeout
 }
 
 func getVMMPath(vmmType VmmType, binary string, monitors map[string]types.MonitorConfig) (string, error) {
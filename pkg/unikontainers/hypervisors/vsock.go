@@ -0,0 +1,121 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const vsockDialTimeout = 5 * time.Second
+
+var ErrNoVSockDevice = errors.New("monitor has no vsock device")
+
+// dialVSock connects to the given port of the guest with the given CID
+// through the vhost-vsock device of the host.
+func dialVSock(cid uint32, port uint32) (io.ReadWriteCloser, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vsock socket: %w", err)
+	}
+	err = unix.Connect(fd, &unix.SockaddrVM{CID: cid, Port: port})
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to connect to vsock %d:%d: %w", cid, port, err)
+	}
+
+	return os.NewFile(uintptr(fd), fmt.Sprintf("vsock:%d:%d", cid, port)), nil
+}
+
+// guestCID retrieves the CID of the vhost-vsock device from the command
+// line of the monitor process with the given pid, where it is set with the
+// given option (e.g. "guest-cid" for QEMU).
+func guestCID(pid int, option string) (uint32, error) {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, arg := range bytes.Split(cmdline, []byte{0}) {
+		for _, opt := range strings.Split(string(arg), ",") {
+			value, found := strings.CutPrefix(opt, option+"=")
+			if !found {
+				continue
+			}
+			cid, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s %q: %w", option, value, err)
+			}
+			return uint32(cid), nil
+		}
+	}
+
+	return 0, ErrNoVSockDevice
+}
+
+// dialFirecrackerVSock connects to the given port of the guest through the
+// unix socket that Firecracker exposes for its vsock device. The connection
+// is established with the CONNECT handshake that Firecracker expects for
+// host-initiated connections.
+func dialFirecrackerVSock(udsPath string, port uint32) (io.ReadWriteCloser, error) {
+	conn, err := net.DialTimeout("unix", udsPath, vsockDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	err = conn.SetDeadline(time.Now().Add(vsockDialTimeout))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_, err = fmt.Fprintf(conn, "CONNECT %d\n", port)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Read the reply byte by byte, since anything after the newline
+	// belongs to the connection with the guest.
+	var reply []byte
+	b := make([]byte, 1)
+	for {
+		_, err = conn.Read(b)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to read vsock handshake reply: %w", err)
+		}
+		if b[0] == '\n' {
+			break
+		}
+		reply = append(reply, b[0])
+	}
+	if !strings.HasPrefix(string(reply), "OK ") {
+		conn.Close()
+		return nil, fmt.Errorf("vsock connection to port %d refused: %q", port, string(reply))
+	}
+	err = conn.SetDeadline(time.Time{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hypervisors
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFirecrackerVSock accepts a single connection on a unix socket, like the
+// vsock device of Firecracker does, and replies to the CONNECT request with
+// reply. Afterwards, it echoes back anything it receives.
+func stubFirecrackerVSock(t *testing.T, reply string) (string, <-chan string) {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), FCVSockFilename)
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	requests := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		requests <- line
+		_, _ = io.WriteString(conn, reply)
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	return socketPath, requests
+}
+
+func TestDialFirecrackerVSock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepted", func(t *testing.T) {
+		t.Parallel()
+		socketPath, requests := stubFirecrackerVSock(t, "OK 1073741824\nhello")
+		conn, err := dialFirecrackerVSock(socketPath, 1025)
+		require.NoError(t, err)
+		defer conn.Close()
+		assert.Equal(t, "CONNECT 1025\n", <-requests)
+
+		// Data following the handshake reply belong to the guest
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(buf))
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		socketPath, _ := stubFirecrackerVSock(t, "\n")
+		_, err := dialFirecrackerVSock(socketPath, 1025)
+		assert.Error(t, err)
+	})
+
+	t.Run("no socket", func(t *testing.T) {
+		t.Parallel()
+		_, err := dialFirecrackerVSock(filepath.Join(t.TempDir(), "missing.sock"), 1025)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/solo5"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/unikernels"
+)
+
+// selectMonitor returns the monitor that will run the unikernel. This is
+// the requested monitor, unless it is not installed. In that case, it is
+// the first monitor in the fallback list of the requested monitor, which
+// is installed and usable. If there is no such monitor, the requested one
+// is returned and the container fails later, as it would without fallback.
+func selectMonitor(requested string, monitors map[string]types.MonitorConfig,
+	usable func(monitor string, vmm types.VMM) bool) string {
+
+	_, err := hypervisors.NewVMM(hypervisors.VmmType(requested), monitors)
+	if !errors.Is(err, hypervisors.ErrVMMNotInstalled) {
+		return requested
+	}
+
+	for _, candidate := range monitors[requested].Fallback {
+		vmm, err := hypervisors.NewVMM(hypervisors.VmmType(candidate), monitors)
+		if err != nil {
+			uniklog.WithError(err).Debugf("can not use fallback monitor %s", candidate)
+			continue
+		}
+		if !usable(candidate, vmm) {
+			uniklog.Debugf("fallback monitor %s can not run the unikernel", candidate)
+			continue
+		}
+		uniklog.Warnf("monitor %s is not installed, using %s instead", requested, candidate)
+		return candidate
+	}
+
+	uniklog.Warnf("monitor %s is not installed and there is no usable fallback monitor", requested)
+	return requested
+}
+
+// fallbackUsable returns a function that checks if a fallback monitor
+// supports the type of the unikernel and the rootfs that the annotations
+// request for the guest.
+func fallbackUsable(bundle string, spec *specs.Spec, unikernelType string,
+	annot map[string]string, cfg *UruncConfig) func(string, types.VMM) bool {
+
+	return func(monitor string, vmm types.VMM) bool {
+		if !unikernels.SupportsMonitor(unikernelType, monitor) {
+			return false
+		}
+		unikernel, err := unikernels.New(unikernelType, annot[annotVersion])
+		if err != nil {
+			return false
+		}
+		if spec.Root == nil {
+			return true
+		}
+		rootfsDir, err := resolveAgainstBase(filepath.Clean(bundle), filepath.Clean(spec.Root.Path))
+		if err != nil {
+			return false
+		}
+
+		return supportsRootfs(bundle, rootfsDir, annot, unikernel, vmm, cfg.ExtraBins["virtiofsd"].Path)
+	}
+}
+
+// solo5Devices returns the devices that the given unikernel declares in its
+// Solo5 manifest, if the unikernel runs on top of a Solo5 tender. In any
+// other case, or if the manifest can not be read, it returns no devices and
+// the unikernels use their default device names.
+func solo5Devices(vmmType string, unikernelPath string) types.Solo5Devices {
+	switch hypervisors.VmmType(vmmType) {
+	case hypervisors.HvtVmm, hypervisors.SptVmm, hypervisors.SandboxVmm:
+	default:
+		return types.Solo5Devices{}
+	}
+
+	manifest, err := solo5.ReadManifest(unikernelPath)
+	if err != nil {
+		uniklog.WithError(err).Debugf("could not read the solo5 manifest of %s", unikernelPath)
+		return types.Solo5Devices{}
+	}
+
+	return types.Solo5Devices{
+		Block: manifest.BlockDevices(),
+		Net:   manifest.NetDevices(),
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestSelectMonitor(t *testing.T) {
+	// Make sure that only the monitors with a configured path are installed
+	t.Setenv("PATH", t.TempDir())
+	monitors := map[string]types.MonitorConfig{
+		"hvt":  {Fallback: []string{"firecracker", "qemu", "spt"}},
+		"qemu": {BinaryPath: "/opt/qemu-system-x86_64"},
+		"spt":  {BinaryPath: "/opt/solo5-spt"},
+	}
+	always := func(string, types.VMM) bool { return true }
+
+	t.Run("requested monitor is installed", func(t *testing.T) {
+		assert.Equal(t, "qemu", selectMonitor("qemu", monitors, always))
+	})
+
+	t.Run("first installed fallback", func(t *testing.T) {
+		assert.Equal(t, "qemu", selectMonitor("hvt", monitors, always))
+	})
+
+	t.Run("first usable fallback", func(t *testing.T) {
+		var tried []string
+		usable := func(monitor string, _ types.VMM) bool {
+			tried = append(tried, monitor)
+			return monitor == "spt"
+		}
+		assert.Equal(t, "spt", selectMonitor("hvt", monitors, usable))
+		assert.Equal(t, []string{"qemu", "spt"}, tried)
+	})
+
+	t.Run("no usable fallback", func(t *testing.T) {
+		never := func(string, types.VMM) bool { return false }
+		assert.Equal(t, "hvt", selectMonitor("hvt", monitors, never))
+	})
+
+	t.Run("no fallback", func(t *testing.T) {
+		assert.Equal(t, "firecracker", selectMonitor("firecracker", monitors, always))
+	})
+}
+
+func TestFallbackUsable(t *testing.T) {
+	t.Parallel()
+	spec := &specs.Spec{}
+	cfg := defaultUruncConfig()
+
+	usable := fallbackUsable(t.TempDir(), spec, "rumprun", map[string]string{}, cfg)
+	assert.True(t, usable("spt", nil))
+	assert.False(t, usable("qemu", nil))
+
+	usable = fallbackUsable(t.TempDir(), spec, "unknown", map[string]string{}, cfg)
+	assert.False(t, usable("qemu", nil))
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
 	"golang.org/x/sys/unix"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -215,6 +216,30 @@ func fileFromHost(monRootfs string, hostPath string, target string, mFlags int,
 	return nil
 }
 
+// setupControlDir creates the control directory of the monitor under the
+// base dir of the container and bind mounts it inside monRootfs, so that
+// the control sockets of the monitor are reachable from the host.
+func setupControlDir(ctrlDir string, monRootfs string, uid uint32, gid uint32) error {
+	err := os.MkdirAll(ctrlDir, 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create control dir %s: %w", ctrlDir, err)
+	}
+
+	// The monitor might run as a non-root user
+	err = os.Chown(ctrlDir, int(uid), int(gid))
+	if err != nil {
+		return fmt.Errorf("failed to chown control dir %s: %w", ctrlDir, err)
+	}
+
+	dstDir := filepath.Join(monRootfs, hypervisors.ControlDir)
+	err = bindMountFile(ctrlDir, dstDir, "", 0, unix.MS_BIND, true)
+	if err != nil {
+		return fmt.Errorf("failed to bind mount control dir: %w", err)
+	}
+
+	return nil
+}
+
 // bindMountFile bind mounts a file/directory to a new path
 func bindMountFile(hostPath string, dstDir string, dstPath string, perm uint32, mFlags int, isDir bool) error {
 	var mountTarget string
@@ -364,6 +389,23 @@ func mountVolumes(rootfsPath string, mounts []specs.Mount) error {
 	return nil
 }
 
+// isReadOnlyMount returns true if the options of a mount entry from the
+// container's configuration make it read-only. As in mountVolumes, the last
+// of the "ro" and "rw" options wins.
+func isReadOnlyMount(options []string) bool {
+	readOnly := false
+	for _, o := range options {
+		switch o {
+		case "ro":
+			readOnly = true
+		case "rw":
+			readOnly = false
+		}
+	}
+
+	return readOnly
+}
+
 // mapMountFlag retrieves the mount flags of a mount entry
 // from the container's configuration
 func mapMountFlag(value string) (mountFlagStruct, bool) {
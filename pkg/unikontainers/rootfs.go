@@ -45,10 +45,13 @@ func newRootfsResult(rootfsType string, path string, mountedPath string, monRoot
 	}
 }
 
-// tryInitrd checks for initrd-based rootfs based on annotation values
+// tryInitrd checks for initrd-based rootfs based on annotation values.
+// Some unikernels (e.g. Hermit) use the initrd for other purposes than
+// a rootfs and therefore, the initrd is the rootfs only for the
+// unikernels that support it as a filesystem.
 func (rs *rootfsSelector) tryInitrd() (types.RootfsParams, bool) {
 	initrdPath := rs.annot[annotInitrd]
-	if initrdPath == "" {
+	if initrdPath == "" || !rs.unikernel.SupportsFS("initrd") {
 		return types.RootfsParams{}, false
 	}
 
@@ -189,7 +192,7 @@ func switchMonRootfs(res types.RootfsParams, bundle string) (types.RootfsParams,
 
 // chooseRootfs determines the best rootfs configuration based on available options
 // Priority order:
-//  1. Initrd (if specified)
+//  1. Initrd (if specified and supported)
 //  2. Explicit block device annotation (if mounted at /)
 //  3. Container rootfs as block device (if MountRootfs=true and supported)
 //  4. Container rootfs as shared-fs: virtiofs > 9pfs (if MountRootfs=true and supported)
@@ -234,6 +237,35 @@ func chooseRootfs(bundle string, cntrRootfs string, annot map[string]string,
 
 }
 
+// supportsRootfs returns true if the guest rootfs that the annotations
+// request can be set up with the given unikernel and monitor. Contrary to
+// chooseRootfs, it does not prepare anything for the rootfs.
+func supportsRootfs(bundle string, cntrRootfs string, annot map[string]string,
+	unikernel types.Unikernel, vmm types.VMM, vfsdPath string) bool {
+
+	selector := &rootfsSelector{
+		bundle:     bundle,
+		cntrRootfs: cntrRootfs,
+		annot:      annot,
+		unikernel:  unikernel,
+		vmm:        vmm,
+		vfsdPath:   vfsdPath,
+	}
+
+	if _, ok := selector.tryInitrd(); ok {
+		return true
+	}
+	if _, ok := selector.tryExplicitBlock(); ok {
+		return true
+	}
+	if !selector.shouldMountContainerRootfs() {
+		return true
+	}
+	_, ok := selector.tryContainerRootfs()
+
+	return ok
+}
+
 // pivotRootfs changes rootfs with pivot
 // It should be called with CWD being the new rootfs
 func pivotRootfs(newRoot string) error {
@@ -314,14 +346,18 @@ func changeRoot(rootfsDir string, pivot bool) error {
 // essentially sets up the devices (KVM, snapshotter block device) that are required
 // for the guest execution and any other files (e.g. binaries).
 func prepareMonRootfs(monRootfs string, monitorPath string, monitorDataPath string, needsKVM bool, needsTAP bool) error {
-	err := fileFromHost(monRootfs, monitorPath, "", unix.MS_BIND|unix.MS_PRIVATE, false)
-	if err != nil {
-		return err
+	var err error
+	// Monitors without a userspace process (e.g. hedge) have no binary
+	if monitorPath != "" {
+		err = fileFromHost(monRootfs, monitorPath, "", unix.MS_BIND|unix.MS_PRIVATE, false)
+		if err != nil {
+			return err
+		}
 	}
 
 	// TODO: Remove these when we switch to static binaries
 	monitorName := filepath.Base(monitorPath)
-	if monitorName != "firecracker" {
+	if monitorPath != "" && monitorName != "firecracker" {
 		err = fileFromHost(monRootfs, "/lib", "", unix.MS_BIND|unix.MS_PRIVATE, false)
 		if err != nil {
 			return err
@@ -52,6 +52,16 @@ func setupSharedfsBasedRootfs(rfs types.RootfsParams, vfsdBin string, mounts []s
 		return err
 	}
 
+	if rfs.ReadOnly {
+		// Remount the rootfs read-only only after mounting the volumes,
+		// since their mount points might need to get created. The
+		// remount does not affect the volumes.
+		err = unix.Mount(newCntrRootfs, newCntrRootfs, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY, "")
+		if err != nil {
+			return fmt.Errorf("failed to remount %s read-only: %w", newCntrRootfs, err)
+		}
+	}
+
 	if rfs.Type == "virtiofs" {
 		// Get the virtiofsd binary from host in monRootfs
 		err = fileFromHost(rfs.MonRootfs, vfsdBin, "", unix.MS_BIND|unix.MS_PRIVATE, false)
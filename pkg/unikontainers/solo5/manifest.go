@@ -0,0 +1,179 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package solo5 reads the manifest of Solo5 unikernels. Every Solo5
+// unikernel embeds a manifest in its ELF binary, which declares the names
+// and the types of the devices that the unikernel expects. The tenders of
+// Solo5 attach a device only if its name exists in the manifest.
+package solo5
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	manifestSection = ".note.solo5.manifest"
+	noteName        = "Solo5"
+	// The type of the manifest note ("MFT1")
+	noteTypeManifest = 0x3154464d
+	manifestVersion  = 1
+	// The size of the header of the manifest (version and number of entries)
+	manifestHeaderSize = 8
+	// The size of an entry of the manifest, along with its padding
+	entrySize = 96
+	// The size of the name field in an entry, including the NUL terminator
+	entryNameSize = 68
+	// The maximum number of entries in a manifest
+	maxEntries = 64
+)
+
+var ErrNoManifest = errors.New("solo5 manifest not found")
+
+type DeviceType uint32
+
+const (
+	DeviceBlock DeviceType = 1
+	DeviceNet   DeviceType = 2
+)
+
+// Device is a device that a Solo5 unikernel declares in its manifest
+type Device struct {
+	Name string
+	Type DeviceType
+}
+
+// Manifest holds the devices of a Solo5 manifest, in the order of their
+// declaration.
+type Manifest struct {
+	Devices []Device
+}
+
+// BlockDevices returns the names of the declared block devices
+func (m *Manifest) BlockDevices() []string {
+	return m.deviceNames(DeviceBlock)
+}
+
+// NetDevices returns the names of the declared network devices
+func (m *Manifest) NetDevices() []string {
+	return m.deviceNames(DeviceNet)
+}
+
+func (m *Manifest) deviceNames(devType DeviceType) []string {
+	var names []string
+	for _, dev := range m.Devices {
+		if dev.Type == devType {
+			names = append(names, dev.Name)
+		}
+	}
+
+	return names
+}
+
+// ReadManifest reads the Solo5 manifest of the given ELF binary. It
+// returns ErrNoManifest if the binary does not contain a manifest.
+func ReadManifest(path string) (*Manifest, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	section := file.Section(manifestSection)
+	if section == nil {
+		return nil, ErrNoManifest
+	}
+	data, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s section: %w", manifestSection, err)
+	}
+
+	// The notes of Solo5 are aligned to 8 bytes, instead of the usual 4.
+	noteAlign := uint64(4)
+	if section.Addralign >= 8 {
+		noteAlign = 8
+	}
+	desc, err := findNote(data, file.ByteOrder, noteAlign)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseManifest(desc, file.ByteOrder)
+}
+
+// findNote returns the descriptor of the manifest note in the given note
+// section.
+func findNote(data []byte, order binary.ByteOrder, align uint64) ([]byte, error) {
+	offset := uint64(0)
+	size := uint64(len(data))
+	for offset+12 <= size {
+		nameSize := uint64(order.Uint32(data[offset:]))
+		descSize := uint64(order.Uint32(data[offset+4:]))
+		noteType := order.Uint32(data[offset+8:])
+
+		nameOffset := offset + 12
+		descOffset := alignUp(nameOffset+nameSize, align)
+		if nameOffset+nameSize > size || descOffset+descSize > size {
+			return nil, fmt.Errorf("truncated note in the %s section", manifestSection)
+		}
+
+		name := string(bytes.TrimRight(data[nameOffset:nameOffset+nameSize], "\x00"))
+		if name == noteName && noteType == noteTypeManifest {
+			return data[descOffset : descOffset+descSize], nil
+		}
+		offset = alignUp(descOffset+descSize, align)
+	}
+
+	return nil, ErrNoManifest
+}
+
+// parseManifest parses the descriptor of the manifest note, which has the
+// layout of struct mft in the mft_abi.h header of Solo5.
+func parseManifest(desc []byte, order binary.ByteOrder) (*Manifest, error) {
+	if len(desc) < manifestHeaderSize {
+		return nil, fmt.Errorf("invalid solo5 manifest size %d", len(desc))
+	}
+	version := order.Uint32(desc[0:])
+	if version != manifestVersion {
+		return nil, fmt.Errorf("unsupported solo5 manifest version %d", version)
+	}
+	entries := order.Uint32(desc[4:])
+	if entries > maxEntries || manifestHeaderSize+uint64(entries)*entrySize > uint64(len(desc)) {
+		return nil, fmt.Errorf("invalid number of solo5 manifest entries %d", entries)
+	}
+
+	manifest := &Manifest{}
+	for i := uint32(0); i < entries; i++ {
+		entry := desc[manifestHeaderSize+uint64(i)*entrySize:]
+		name, _, _ := bytes.Cut(entry[:entryNameSize], []byte{0})
+		devType := DeviceType(order.Uint32(entry[entryNameSize:]))
+		// Skip the entries which are reserved for the tenders
+		if devType != DeviceBlock && devType != DeviceNet {
+			continue
+		}
+		manifest.Devices = append(manifest.Devices, Device{
+			Name: string(name),
+			Type: devType,
+		})
+	}
+
+	return manifest, nil
+}
+
+func alignUp(value uint64, align uint64) uint64 {
+	return (value + align - 1) &^ (align - 1)
+}
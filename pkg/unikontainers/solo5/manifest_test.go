@@ -0,0 +1,86 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solo5
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rumprun", func(t *testing.T) {
+		t.Parallel()
+		manifest, err := ReadManifest(filepath.Join("testdata", "rumprun.hvt"))
+		require.NoError(t, err)
+		assert.Equal(t, []Device{
+			{Name: "rootfs", Type: DeviceBlock},
+			{Name: "tap", Type: DeviceNet},
+		}, manifest.Devices)
+		assert.Equal(t, []string{"rootfs"}, manifest.BlockDevices())
+		assert.Equal(t, []string{"tap"}, manifest.NetDevices())
+	})
+
+	t.Run("mirage with reserved entry", func(t *testing.T) {
+		t.Parallel()
+		manifest, err := ReadManifest(filepath.Join("testdata", "mirage.hvt"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"storage", "data"}, manifest.BlockDevices())
+		assert.Equal(t, []string{"service"}, manifest.NetDevices())
+	})
+
+	t.Run("no manifest", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReadManifest(filepath.Join("testdata", "no-manifest.elf"))
+		assert.ErrorIs(t, err, ErrNoManifest)
+	})
+
+	t.Run("not an ELF", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReadManifest(filepath.Join("testdata", "fixture.c"))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNoManifest)
+	})
+}
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+	order := binary.LittleEndian
+
+	desc := make([]byte, manifestHeaderSize+entrySize)
+	order.PutUint32(desc[0:], manifestVersion)
+	order.PutUint32(desc[4:], 1)
+	copy(desc[manifestHeaderSize:], "storage")
+	order.PutUint32(desc[manifestHeaderSize+entryNameSize:], uint32(DeviceBlock))
+
+	manifest, err := parseManifest(desc, order)
+	require.NoError(t, err)
+	assert.Equal(t, []Device{{Name: "storage", Type: DeviceBlock}}, manifest.Devices)
+
+	_, err = parseManifest(desc[:4], order)
+	assert.Error(t, err)
+
+	_, err = parseManifest(desc[:manifestHeaderSize+entrySize-1], order)
+	assert.Error(t, err)
+
+	order.PutUint32(desc[0:], 2)
+	_, err = parseManifest(desc, order)
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+/*
+ * Source of the ELF fixtures of the Solo5 manifest tests. The manifest
+ * follows the layout of include/mft_abi.h of Solo5. The fixtures are built
+ * with:
+ *
+ * gcc -Os -nostdlib -static -Wl,--build-id=none,-N -s -DENTRIES=2 \
+ *     -D'DEVICES={.name="rootfs",.type=1},{.name="tap",.type=2}' \
+ *     -o rumprun.hvt fixture.c
+ * gcc -Os -nostdlib -static -Wl,--build-id=none,-N -s -DENTRIES=4 \
+ *     -D'DEVICES={.name="service",.type=2},{.name="storage",.type=1},{.name="reserved",.type=1u<<30},{.name="data",.type=1}' \
+ *     -o mirage.hvt fixture.c
+ *
+ * The no-manifest.elf fixture is built in the same way, from a source with
+ * only the _start function below.
+ */
+#include <stdint.h>
+#include <stdbool.h>
+#define MFT_NAME_SIZE 68
+struct mft_block_basic { uint64_t capacity; uint16_t block_size; int hostfd; };
+struct mft_net_basic { uint8_t mac[6]; uint16_t mtu; int hostfd; };
+struct mft_entry {
+	char name[MFT_NAME_SIZE];
+	uint32_t type;
+	union { struct mft_block_basic b; struct mft_net_basic n; } u;
+	bool attached;
+};
+struct mft { uint32_t version; uint32_t entries; struct mft_entry e[ENTRIES]; };
+struct mft1_note { uint32_t namesz, descsz, type; char n[8]; struct mft m; };
+_Static_assert(sizeof(struct mft_entry) == 96, "entry size");
+_Static_assert(__builtin_offsetof(struct mft1_note, m) == 24, "desc offset");
+
+const struct mft1_note __solo5_mft1_note
+	__attribute__((section(".note.solo5.manifest"), aligned(8), used)) = {
+	.namesz = 6, .descsz = sizeof(struct mft), .type = 0x3154464d,
+	.n = "Solo5",
+	.m = { .version = 1, .entries = ENTRIES, .e = { DEVICES } },
+};
+
+void _start(void) { for (;;); }
@@ -0,0 +1,264 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs2"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	runctypes "github.com/opencontainers/runc/types"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+// Stats holds the resource usage statistics of a unikernel container. It
+// follows the stats format of runc, so that existing collectors can parse
+// it, and extends it with the counters that the monitor reports for the
+// guest.
+type Stats struct {
+	runctypes.Stats
+	Monitor map[string]uint64 `json:"monitor,omitempty"`
+}
+
+// Stats returns the resource usage statistics of the unikernel container.
+// The CPU, memory, pids and IO statistics come from the cgroup (either v1 or
+// v2) of the monitor process, the network statistics from the tap devices in the
+// network namespace of the monitor and, if the monitor supports it, the
+// guest statistics from the monitor itself.
+func (u *Unikontainer) Stats() (*Stats, error) {
+	if !u.isRunning() {
+		return nil, fmt.Errorf("container %s is not running", u.State.ID)
+	}
+
+	manager, err := cgroupManager(u.State.Pid)
+	if err != nil {
+		return nil, err
+	}
+	cgroupStats, err := manager.GetStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cgroup stats of container %s: %w", u.State.ID, err)
+	}
+	stats := &Stats{Stats: convertCgroupStats(cgroupStats)}
+
+	stats.NetworkInterfaces, err = tapStats(u.State.Pid)
+	if err != nil {
+		uniklog.Warnf("failed to get network stats of %s: %v", u.State.ID, err)
+	}
+
+	vmmType := u.State.Annotations[annotHypervisor]
+	vmm, err := hypervisors.NewVMM(hypervisors.VmmType(vmmType), u.UruncCfg.Monitors)
+	if err != nil {
+		return nil, err
+	}
+	if reader, ok := vmm.(types.VMMStatsReader); ok {
+		stats.Monitor, err = reader.Stats(u.State.Pid)
+		if err != nil {
+			uniklog.Warnf("failed to get %s stats of %s: %v", vmmType, u.State.ID, err)
+		}
+	}
+
+	return stats, nil
+}
+
+// cgroupManager returns the manager of the cgroup of the process with the
+// given pid, either in the unified hierarchy of cgroup v2 or, like runc, in
+// the hierarchies of cgroup v1.
+func cgroupManager(pid int) (cgroups.Manager, error) {
+	if cgroups.IsCgroup2UnifiedMode() {
+		cgroup, err := hypervisors.ProcessCgroup(pid)
+		if err != nil {
+			return nil, err
+		}
+		return fs2.NewManager(&configs.Cgroup{}, cgroup)
+	}
+
+	procCgroups, err := cgroups.ParseCgroupFile(filepath.Join("/proc", strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	paths := cgroupV1Paths(procCgroups, func(subsystem string) (string, string, error) {
+		return cgroups.FindCgroupMountpointAndRoot("", subsystem)
+	})
+
+	return fs.NewManager(&configs.Cgroup{Resources: &configs.Resources{}}, paths)
+}
+
+// cgroupV1Paths returns the paths of the cgroup v1 of a process in the
+// hierarchy of each subsystem, given the cgroups of the process in the
+// format of /proc/<pid>/cgroup. The subsystems which are not mounted are
+// skipped.
+func cgroupV1Paths(procCgroups map[string]string, mountpoint func(string) (string, string, error)) map[string]string {
+	paths := make(map[string]string)
+	for subsystem, cgroup := range procCgroups {
+		// The empty subsystem is the unified hierarchy of hybrid hosts
+		if subsystem == "" {
+			continue
+		}
+		mnt, root, err := mountpoint(subsystem)
+		if err != nil {
+			continue
+		}
+		relCgroup, err := filepath.Rel(root, cgroup)
+		if err != nil {
+			continue
+		}
+		paths[subsystem] = filepath.Join(mnt, relCgroup)
+	}
+
+	return paths
+}
+
+// convertCgroupStats converts the cgroup statistics to the stats format
+// of runc.
+// Based on runc:
+// https://github.com/opencontainers/runc/blob/v1.2.8/events.go#L118
+func convertCgroupStats(cg *cgroups.Stats) runctypes.Stats {
+	var s runctypes.Stats
+	s.Pids.Current = cg.PidsStats.Current
+	s.Pids.Limit = cg.PidsStats.Limit
+
+	s.CPU.Usage.Kernel = cg.CpuStats.CpuUsage.UsageInKernelmode
+	s.CPU.Usage.User = cg.CpuStats.CpuUsage.UsageInUsermode
+	s.CPU.Usage.Total = cg.CpuStats.CpuUsage.TotalUsage
+	s.CPU.Usage.Percpu = cg.CpuStats.CpuUsage.PercpuUsage
+	s.CPU.Usage.PercpuKernel = cg.CpuStats.CpuUsage.PercpuUsageInKernelmode
+	s.CPU.Usage.PercpuUser = cg.CpuStats.CpuUsage.PercpuUsageInUsermode
+	s.CPU.Throttling.Periods = cg.CpuStats.ThrottlingData.Periods
+	s.CPU.Throttling.ThrottledPeriods = cg.CpuStats.ThrottlingData.ThrottledPeriods
+	s.CPU.Throttling.ThrottledTime = cg.CpuStats.ThrottlingData.ThrottledTime
+	s.CPU.PSI = cg.CpuStats.PSI
+
+	s.CPUSet = runctypes.CPUSet(cg.CPUSetStats)
+
+	s.Memory.Cache = cg.MemoryStats.Cache
+	s.Memory.Kernel = convertMemoryEntry(cg.MemoryStats.KernelUsage)
+	s.Memory.KernelTCP = convertMemoryEntry(cg.MemoryStats.KernelTCPUsage)
+	s.Memory.Swap = convertMemoryEntry(cg.MemoryStats.SwapUsage)
+	s.Memory.Usage = convertMemoryEntry(cg.MemoryStats.Usage)
+	s.Memory.Raw = cg.MemoryStats.Stats
+	s.Memory.PSI = cg.MemoryStats.PSI
+
+	s.Blkio.IoServiceBytesRecursive = convertBlkioEntry(cg.BlkioStats.IoServiceBytesRecursive)
+	s.Blkio.IoServicedRecursive = convertBlkioEntry(cg.BlkioStats.IoServicedRecursive)
+	s.Blkio.IoQueuedRecursive = convertBlkioEntry(cg.BlkioStats.IoQueuedRecursive)
+	s.Blkio.IoServiceTimeRecursive = convertBlkioEntry(cg.BlkioStats.IoServiceTimeRecursive)
+	s.Blkio.IoWaitTimeRecursive = convertBlkioEntry(cg.BlkioStats.IoWaitTimeRecursive)
+	s.Blkio.IoMergedRecursive = convertBlkioEntry(cg.BlkioStats.IoMergedRecursive)
+	s.Blkio.IoTimeRecursive = convertBlkioEntry(cg.BlkioStats.IoTimeRecursive)
+	s.Blkio.SectorsRecursive = convertBlkioEntry(cg.BlkioStats.SectorsRecursive)
+	s.Blkio.PSI = cg.BlkioStats.PSI
+
+	s.Hugetlb = make(map[string]runctypes.Hugetlb)
+	for k, v := range cg.HugetlbStats {
+		s.Hugetlb[k] = runctypes.Hugetlb{
+			Usage:   v.Usage,
+			Max:     v.MaxUsage,
+			Failcnt: v.Failcnt,
+		}
+	}
+
+	return s
+}
+
+func convertMemoryEntry(c cgroups.MemoryData) runctypes.MemoryEntry {
+	return runctypes.MemoryEntry{
+		Limit:   c.Limit,
+		Usage:   c.Usage,
+		Max:     c.MaxUsage,
+		Failcnt: c.Failcnt,
+	}
+}
+
+func convertBlkioEntry(c []cgroups.BlkioStatEntry) []runctypes.BlkioEntry {
+	var out []runctypes.BlkioEntry
+	for _, e := range c {
+		out = append(out, runctypes.BlkioEntry(e))
+	}
+	return out
+}
+
+// tapStats returns the statistics of the tap devices in the network
+// namespace of the process with the given pid. Like runc does for the host
+// side of the veth pair, the statistics are the ones that the host sees.
+// Therefore, the received bytes of the tap device are the bytes that the
+// guest transmitted.
+func tapStats(pid int) ([]*runctypes.NetworkInterface, error) {
+	netDev, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "net", "dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer netDev.Close()
+
+	ifaces, err := parseNetDev(netDev)
+	if err != nil {
+		return nil, err
+	}
+	var taps []*runctypes.NetworkInterface
+	for _, iface := range ifaces {
+		if strings.Contains(iface.Name, "tap") {
+			taps = append(taps, iface)
+		}
+	}
+
+	return taps, nil
+}
+
+// parseNetDev parses the interface statistics in the format of
+// /proc/net/dev.
+func parseNetDev(r io.Reader) ([]*runctypes.NetworkInterface, error) {
+	var ifaces []*runctypes.NetworkInterface
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, counters, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			// Skip the header lines
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("invalid statistics for interface %s", strings.TrimSpace(name))
+		}
+		values := make([]uint64, 16)
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid statistics for interface %s: %w", strings.TrimSpace(name), err)
+			}
+			values[i] = v
+		}
+		ifaces = append(ifaces, &runctypes.NetworkInterface{
+			Name:      strings.TrimSpace(name),
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		})
+	}
+
+	return ifaces, scanner.Err()
+}
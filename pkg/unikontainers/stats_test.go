@@ -0,0 +1,94 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	runctypes "github.com/opencontainers/runc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetDev(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid statistics", func(t *testing.T) {
+		t.Parallel()
+		netDev := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+tap0_urunc:    1500      10    1    2    0     0          0         0     3000      20    3    4    0     0       0          0
+`
+		ifaces, err := parseNetDev(strings.NewReader(netDev))
+		require.NoError(t, err)
+		require.Len(t, ifaces, 2)
+		assert.Equal(t, "lo", ifaces[0].Name)
+		assert.Equal(t, &runctypes.NetworkInterface{
+			Name:      "tap0_urunc",
+			RxBytes:   1500,
+			RxPackets: 10,
+			RxErrors:  1,
+			RxDropped: 2,
+			TxBytes:   3000,
+			TxPackets: 20,
+			TxErrors:  3,
+			TxDropped: 4,
+		}, ifaces[1])
+	})
+
+	t.Run("truncated statistics", func(t *testing.T) {
+		t.Parallel()
+		_, err := parseNetDev(strings.NewReader("eth0: 1 2 3\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestCgroupV1Paths(t *testing.T) {
+	t.Parallel()
+	procCgroups := map[string]string{
+		"cpu":          "/system.slice/containerd.service/cntr",
+		"cpuacct":      "/system.slice/containerd.service/cntr",
+		"memory":       "/system.slice/containerd.service/cntr",
+		"pids":         "/cntr",
+		"name=systemd": "/system.slice/containerd.service/cntr",
+		"net_prio":     "/cntr",
+		"":             "/system.slice/containerd.service",
+	}
+	mounts := map[string][2]string{
+		"cpu":          {"/sys/fs/cgroup/cpu,cpuacct", "/"},
+		"cpuacct":      {"/sys/fs/cgroup/cpu,cpuacct", "/"},
+		"memory":       {"/sys/fs/cgroup/memory", "/"},
+		"pids":         {"/sys/fs/cgroup/pids", "/"},
+		"name=systemd": {"/sys/fs/cgroup/systemd", "/system.slice"},
+	}
+	mountpoint := func(subsystem string) (string, string, error) {
+		mount, ok := mounts[subsystem]
+		if !ok {
+			return "", "", fmt.Errorf("subsystem %s is not mounted", subsystem)
+		}
+		return mount[0], mount[1], nil
+	}
+
+	assert.Equal(t, map[string]string{
+		"cpu":          "/sys/fs/cgroup/cpu,cpuacct/system.slice/containerd.service/cntr",
+		"cpuacct":      "/sys/fs/cgroup/cpu,cpuacct/system.slice/containerd.service/cntr",
+		"memory":       "/sys/fs/cgroup/memory/system.slice/containerd.service/cntr",
+		"pids":         "/sys/fs/cgroup/pids/cntr",
+		"name=systemd": "/sys/fs/cgroup/systemd/containerd.service/cntr",
+	}, cgroupV1Paths(procCgroups, mountpoint))
+}
@@ -14,11 +14,14 @@
 
 package types
 
+import "io"
+
 type Unikernel interface {
 	Init(UnikernelParams) error
 	CommandString() (string, error)
 	SupportsBlock() bool
 	SupportsFS(string) bool
+	SupportedMonitors() []string
 	MonitorNetCli(string, string) string
 	MonitorBlockCli() []MonitorBlockArgs
 	MonitorCli() MonitorCliArgs
@@ -27,18 +30,47 @@ type Unikernel interface {
 type VMM interface {
 	Execve(args ExecArgs, ukernel Unikernel) error
 	Stop(int) error
+	Kill(int) error
 	Path() string
 	UsesKVM() bool
 	SupportsSharedfs(string) bool
+	SupportsDiskFormat(string) bool
 	Ok() error
 }
 
+// VMMPauser is implemented by the monitors which can pause and resume the
+// execution of a guest.
+type VMMPauser interface {
+	Pause(int) error
+	Resume(int) error
+}
+
+// VMMStatsReader is implemented by the monitors which report statistics
+// about the guest. The statistics map the name of each counter to its value.
+type VMMStatsReader interface {
+	Stats(pid int) (map[string]uint64, error)
+}
+
+// VMMVSockDialer is implemented by the monitors which can connect from the
+// host to a vsock port of the guest.
+type VMMVSockDialer interface {
+	DialVSock(pid int, port uint32) (io.ReadWriteCloser, error)
+}
+
+// BlockMountLimiter is implemented by the unikernels which mount only a
+// limited number of the block devices of the guest, starting from the
+// first one, over the given monitor and with the given Solo5 manifest.
+type BlockMountLimiter interface {
+	MaxBlockMounts(monitor string, solo5Devs Solo5Devices) int
+}
+
 type NetDevParams struct {
-	IP      string // The veth device IP
-	Mask    string // The veth device mask
-	Gateway string // The veth device gateway
-	MAC     string // The MAC address of the guest network device
-	TapDev  string // The tap device name
+	IP      string   // The veth device IP
+	Mask    string   // The veth device mask
+	Gateway string   // The veth device gateway
+	MAC     string   // The MAC address of the guest network device
+	TapDev  string   // The tap device name
+	DNS     []string // The IPv4 DNS servers of the guest
 }
 
 type BlockDevParams struct {
@@ -46,11 +78,21 @@ type BlockDevParams struct {
 	MountPoint string
 	FsType     string
 	ID         string
+	ReadOnly   bool
+	Format     string // The format of the disk image (e.g. raw, qcow2)
+}
+
+// Solo5Devices holds the names of the devices that a Solo5 based unikernel
+// declares in its manifest.
+type Solo5Devices struct {
+	Block []string
+	Net   []string
 }
 
 type SharedfsParams struct {
-	Type string // The type of shared-fs 9p or virtiofs
-	Path string // The path in the host to share with guest
+	Type     string // The type of shared-fs 9p or virtiofs
+	Path     string // The path in the host to share with guest
+	ReadOnly bool   // Share the path read-only with the guest
 }
 
 type RootfsParams struct {
@@ -58,6 +100,7 @@ type RootfsParams struct {
 	Path        string // The path in the host where rootfs resides
 	MountedPath string // The mountpoint in the host where the rootfs is mounted
 	MonRootfs   string // The rootfs for the monitor process
+	ReadOnly    bool   // The rootfs is read-only for the guest
 }
 
 // Specific to Linux
@@ -69,15 +112,19 @@ type ProcessConfig struct {
 
 // UnikernelParams holds the data required to build the unikernels commandline
 type UnikernelParams struct {
-	CmdLine    []string // The cmdline provided by the image
-	EnvVars    []string // The environment variables provided by the image
-	Monitor    string   // The monitor where guest will execute
-	Version    string   // The version of the unikernel
-	InitrdPath string   // The path to the initrd of the unikernel
-	Net        NetDevParams
-	Block      []BlockDevParams
-	Rootfs     RootfsParams  // Information about rootfs
-	ProcConf   ProcessConfig // Information for the process execution inside the guest
+	CmdLine        []string     // The cmdline provided by the image
+	EnvVars        []string     // The environment variables provided by the image
+	Monitor        string       // The monitor where guest will execute
+	Version        string       // The version of the unikernel
+	InitrdPath     string       // The path to the initrd of the unikernel
+	AgentPort      uint32       // The vsock port of the guest agent. When zero, the agent is disabled
+	Hostname       string       // The hostname of the guest
+	UrunitFeatures []string     // The optional features that the urunit of a Linux guest supports
+	Solo5Devs      Solo5Devices // The devices in the manifest of Solo5 based unikernels
+	Net            NetDevParams
+	Block          []BlockDevParams
+	Rootfs         RootfsParams  // Information about rootfs
+	ProcConf       ProcessConfig // Information for the process execution inside the guest
 }
 
 // ExecArgs holds the data required by Execve to start the VMM
@@ -94,6 +141,7 @@ type ExecArgs struct {
 	VAccelType    string   // Specifies the vAccel acceleration type(e.g. vsock). When empty, vAccel is disabled
 	VSockDevPath  string   // The host directory where the fc unix socket is created
 	VSockDevID    int      // The guest-cid
+	AgentVSock    bool     // Add a vsock device for the guest agent, even if vAccel is disabled
 	Net           NetDevParams
 	Sharedfs      SharedfsParams
 }
@@ -107,6 +155,8 @@ type MonitorBlockArgs struct {
 	ID        string
 	Path      string
 	ExactArgs string
+	ReadOnly  bool
+	Format    string
 }
 
 // ExtraBinConfig struct is used to hold specific configuration for extra binaries
@@ -119,8 +169,10 @@ type ExtraBinConfig struct {
 // MonitorConfig struct is used to hold hypervisor specific configuration
 // that is parsed from the urunc config file or state.json annotations
 type MonitorConfig struct {
-	DefaultMemoryMB uint   `toml:"default_memory_mb"`
-	DefaultVCPUs    uint   `toml:"default_vcpus"`
-	BinaryPath      string `toml:"path,omitempty"`      // Optional path to the hypervisor binary
-	DataPath        string `toml:"data_path,omitempty"` // Optional path to the hypervisor data files (e.g. qemu bios stuff)
+	DefaultMemoryMB uint     `toml:"default_memory_mb"`
+	DefaultVCPUs    uint     `toml:"default_vcpus"`
+	BinaryPath      string   `toml:"path,omitempty"`      // Optional path to the hypervisor binary
+	DataPath        string   `toml:"data_path,omitempty"` // Optional path to the hypervisor data files (e.g. qemu bios stuff)
+	ShutdownTimeout uint     `toml:"shutdown_timeout"`    // Seconds to wait for a graceful shutdown of the guest before killing the monitor
+	Fallback        []string `toml:"fallback,omitempty"`  // Monitors to use, in order, if this monitor is not installed
 }
@@ -0,0 +1,140 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const HermitUnikernel string = "hermit"
+
+// Hermit applications get booted by the Hermit loader. The loader is the
+// kernel of the monitor and the application itself is passed as an initrd.
+type Hermit struct {
+	Monitor string
+	Command []string
+	Env     []string
+	Net     HermitNet
+}
+
+type HermitNet struct {
+	Address string
+	Mask    string
+	Gateway string
+}
+
+// CommandString returns the command line of the Hermit kernel. The kernel
+// splits its command line in words like a shell. Before "--", the -ip,
+// -gateway and -mask options configure the network of the kernel and every
+// env=KEY=VALUE word becomes an environment variable of the application.
+// The words after "--" are the arguments of the application.
+func (h *Hermit) CommandString() (string, error) {
+	var words []string
+	if h.Net.Address != "" {
+		words = append(words, "-ip", h.Net.Address)
+		words = append(words, "-gateway", h.Net.Gateway)
+		words = append(words, "-mask", h.Net.Mask)
+	}
+	for _, env := range h.Env {
+		key, _, found := strings.Cut(env, "=")
+		if !found || key == "" {
+			continue
+		}
+		words = append(words, hermitQuote("env="+env))
+	}
+	words = append(words, "--")
+	for _, arg := range h.Command {
+		words = append(words, hermitQuote(arg))
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// hermitQuote wraps a word of the Hermit command line in single quotes, if
+// it contains whitespace or any character with a special meaning for a
+// shell-like split.
+func hermitQuote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n\"'\\") {
+		return word
+	}
+
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+func (h *Hermit) SupportsBlock() bool {
+	return false
+}
+
+func (h *Hermit) SupportedMonitors() []string {
+	return []string{"qemu", "firecracker"}
+}
+
+// Hermit can mount a directory shared through virtiofs. The initrd of
+// Hermit is the application and not a filesystem.
+func (h *Hermit) SupportsFS(fsType string) bool {
+	switch fsType {
+	case "virtiofs":
+		return true
+	default:
+		return false
+	}
+}
+
+// The default virtio-net devices of the monitors are sufficient for Hermit.
+func (h *Hermit) MonitorNetCli(_ string, _ string) string {
+	return ""
+}
+
+func (h *Hermit) MonitorBlockCli() []types.MonitorBlockArgs {
+	return nil
+}
+
+func (h *Hermit) MonitorCli() types.MonitorCliArgs {
+	switch h.Monitor {
+	case "qemu":
+		extraCliArgs := types.MonitorCliArgs{
+			OtherArgs: " -no-reboot",
+		}
+		// On x86, Hermit reports its exit code through the isa-debug-exit device
+		if runtime.GOARCH == "amd64" {
+			extraCliArgs.OtherArgs += " -device isa-debug-exit,iobase=0xf4,iosize=0x04"
+		}
+		return extraCliArgs
+	default:
+		return types.MonitorCliArgs{}
+	}
+}
+
+func (h *Hermit) Init(data types.UnikernelParams) error {
+	h.Monitor = data.Monitor
+	h.Command = data.CmdLine
+	h.Env = data.EnvVars
+
+	if data.Net.IP != "" {
+		h.Net.Address = data.Net.IP
+		h.Net.Mask = data.Net.Mask
+		h.Net.Gateway = data.Net.Gateway
+	}
+
+	return nil
+}
+
+func newHermit() *Hermit {
+	hermitStruct := new(Hermit)
+	return hermitStruct
+}
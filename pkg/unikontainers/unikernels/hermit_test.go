@@ -0,0 +1,86 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestHermitQuote(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"/hello":      "/hello",
+		"PORT=8080":   "PORT=8080",
+		"hello world": "'hello world'",
+		"":            "''",
+		"it's":        `'it'\''s'`,
+		`say "hi"`:    `'say "hi"'`,
+		`C:\path`:     `'C:\path'`,
+		"MSG=a b":     "'MSG=a b'",
+	}
+	for word, expected := range tests {
+		assert.Equal(t, expected, hermitQuote(word), word)
+	}
+}
+
+func TestHermitCommandString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("network, env and args", func(t *testing.T) {
+		t.Parallel()
+		h := newHermit()
+		err := h.Init(types.UnikernelParams{
+			CmdLine: []string{"/httpd", "--msg", "hello world"},
+			EnvVars: []string{"RUST_LOG=debug", "MSG=it's here", "INVALID", "=novalue"},
+			Monitor: "qemu",
+			Net: types.NetDevParams{
+				IP:      "10.0.0.2",
+				Mask:    "255.255.255.0",
+				Gateway: "10.0.0.1",
+			},
+		})
+		require.NoError(t, err)
+
+		cmd, err := h.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "-ip 10.0.0.2 -gateway 10.0.0.1 -mask 255.255.255.0"+
+			` env=RUST_LOG=debug 'env=MSG=it'\''s here' -- /httpd --msg 'hello world'`, cmd)
+	})
+
+	t.Run("no network", func(t *testing.T) {
+		t.Parallel()
+		h := newHermit()
+		require.NoError(t, h.Init(types.UnikernelParams{Monitor: "firecracker"}))
+
+		cmd, err := h.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "--", cmd)
+	})
+}
+
+func TestHermitSupport(t *testing.T) {
+	t.Parallel()
+	h := newHermit()
+	assert.False(t, h.SupportsBlock())
+	assert.True(t, h.SupportsFS("virtiofs"))
+	assert.False(t, h.SupportsFS("initrd"))
+	assert.False(t, h.SupportsFS("9pfs"))
+	assert.Equal(t, types.MonitorCliArgs{}, (&Hermit{Monitor: "firecracker"}).MonitorCli())
+	assert.Contains(t, (&Hermit{Monitor: "qemu"}).MonitorCli().OtherArgs, "-no-reboot")
+}
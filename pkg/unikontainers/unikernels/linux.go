@@ -19,9 +19,11 @@ import (
 	"net"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/urunc-dev/urunc/pkg/unikontainers/agent"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/initrd"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
 )
@@ -36,18 +38,23 @@ const (
 	lpcEndMarker     string = "UCE" // Linux process config end marker
 	blkStartMarker   string = "UBS" // Block-based mounts start marker
 	blkEndMarker     string = "UBE" // Block-based mounts end marker
+	// The feature of urunit to mount block devices read-only
+	urunitReadOnlyFeature string = "ro"
 )
 
 type Linux struct {
-	App        string
-	Command    string
-	Monitor    string
-	Env        []string
-	Net        LinuxNet
-	Blk        []types.BlockDevParams
-	RootFsType string
-	InitrdConf bool
-	ProcConfig types.ProcessConfig
+	App            string
+	Command        string
+	Monitor        string
+	Env            []string
+	Net            LinuxNet
+	Blk            []types.BlockDevParams
+	RootFsType     string
+	RootFsRO       bool
+	InitrdConf     bool
+	AgentPort      uint32
+	ProcConfig     types.ProcessConfig
+	UrunitFeatures []string
 }
 
 type LinuxNet struct {
@@ -74,28 +81,34 @@ func (l *Linux) CommandString() (string, error) {
 	consoleStr := ""
 	// TODO: Check under which conditions console should be set to
 	// ttyS0 or ttyAMA0. Currently, we have noticed that FC requires ttyS0
-	// and Qemu ttyAMA0 for aarch64 while for amd64 both are fine with ttyS0
-	if runtime.GOARCH == "arm64" && l.Monitor == "qemu" {
+	// and Qemu ttyAMA0 for aarch64 while for amd64 both are fine with ttyS0.
+	// Cloud-hypervisor also emulates a PL011 UART on aarch64.
+	if runtime.GOARCH == "arm64" && (l.Monitor == "qemu" || l.Monitor == "cloud-hypervisor") {
 		consoleStr = "console=ttyAMA0"
 	} else {
 		consoleStr = "console=ttyS0"
 	}
 	bootParams += " " + consoleStr
 
+	rootMode := "rw"
+	if l.RootFsRO {
+		rootMode = "ro"
+	}
 	switch l.RootFsType {
 	case "block":
-		rootParams := "root=/dev/vda rw"
+		rootParams := "root=/dev/vda " + rootMode
 		bootParams += " " + rootParams
 	case "initrd":
+		// The initrd gets extracted in a ramfs and it is always writable
 		rootParams := "root=/dev/ram0 rw"
 		rdinit = "rd"
 		bootParams += " " + rootParams
 	case "9pfs":
-		rootParams := "root=fs0 rw rootfstype=9p rootflags="
+		rootParams := "root=fs0 " + rootMode + " rootfstype=9p rootflags="
 		rootParams += "trans=virtio,version=9p2000.L,msize=5000000,cache=mmap,posixacl"
 		bootParams += " " + rootParams
 	case "virtiofs":
-		rootParams := "root=fs0 rw rootfstype=virtiofs"
+		rootParams := "root=fs0 " + rootMode + " rootfstype=virtiofs"
 		bootParams += " " + rootParams
 	}
 	if l.Net.Address != "" {
@@ -121,6 +134,9 @@ func (l *Linux) CommandString() (string, error) {
 	if !IsIPInSubnet(l.Net) {
 		bootParams += " URUNIT_DEFROUTE=1"
 	}
+	if l.InitrdConf && l.AgentPort != 0 {
+		bootParams += fmt.Sprintf(" %s=%d", agent.CmdlineVSockPort, l.AgentPort)
+	}
 	if l.App != "" {
 		initParams := rdinit + "init=" + l.App + " -- " + l.Command
 		bootParams += " " + initParams
@@ -133,8 +149,14 @@ func (l *Linux) SupportsBlock() bool {
 	return true
 }
 
+func (l *Linux) SupportedMonitors() []string {
+	return []string{"qemu", "firecracker", "cloud-hypervisor", "crosvm"}
+}
+
 func (l *Linux) SupportsFS(fsType string) bool {
 	switch fsType {
+	case "initrd":
+		return true
 	case "ext2":
 		return true
 	case "ext3":
@@ -163,20 +185,25 @@ func (l *Linux) MonitorBlockCli() []types.MonitorBlockArgs {
 	case "qemu":
 		for _, aBlock := range l.Blk {
 			bcli1 := fmt.Sprintf(" -device virtio-blk-pci,serial=%s,drive=%s", aBlock.ID, aBlock.ID)
-			bcli2 := fmt.Sprintf(" -drive format=raw,if=none,id=%s,file=%s", aBlock.ID, aBlock.Source)
+			format := aBlock.Format
+			if format == "" {
+				format = "raw"
+			}
+			bcli2 := fmt.Sprintf(" -drive format=%s,if=none,id=%s,file=%s", format, aBlock.ID, aBlock.Source)
+			if aBlock.ReadOnly {
+				bcli2 += ",readonly=on"
+			}
 			blkArgs = append(blkArgs, types.MonitorBlockArgs{
 				ExactArgs: bcli1 + bcli2,
 			})
 		}
-	case "firecracker":
+	case "firecracker", "cloud-hypervisor", "crosvm":
 		for _, aBlock := range l.Blk {
-			id := aBlock.ID
-			if l.Monitor == "firecracker" {
-				id = "FC" + aBlock.ID
-			}
 			blkArgs = append(blkArgs, types.MonitorBlockArgs{
-				ID:   id,
-				Path: aBlock.Source,
+				ID:       monitorBlockID(l.Monitor, aBlock.ID),
+				Path:     aBlock.Source,
+				ReadOnly: aBlock.ReadOnly,
+				Format:   aBlock.Format,
 			})
 		}
 	default:
@@ -196,7 +223,7 @@ func (l *Linux) MonitorCli() types.MonitorCliArgs {
 			extraCliArgs.ExtraInitrd = urunitConfPath
 		}
 		return extraCliArgs
-	case "firecracker":
+	case "firecracker", "cloud-hypervisor", "crosvm":
 		if l.InitrdConf && l.RootFsType != "initrd" {
 			return types.MonitorCliArgs{
 				ExtraInitrd: urunitConfPath,
@@ -217,6 +244,7 @@ func (l *Linux) Init(data types.UnikernelParams) error {
 	l.configureNetwork(data.Net)
 	l.Blk = data.Block
 	l.RootFsType = data.Rootfs.Type
+	l.RootFsRO = data.Rootfs.ReadOnly
 	l.Env = data.EnvVars
 	l.Monitor = data.Monitor
 	l.ProcConfig = data.ProcConf
@@ -225,8 +253,17 @@ func (l *Linux) Init(data types.UnikernelParams) error {
 	// that the init process is based on our urunit
 	// and hence it can handle the information we pass to
 	// it through initrd.
-	l.InitrdConf = strings.Contains(l.App, "urunit")
+	l.InitrdConf = UsesUrunit(data.CmdLine)
 	if l.InitrdConf {
+		l.AgentPort = data.AgentPort
+		l.UrunitFeatures = data.UrunitFeatures
+		// The monitor attaches read-only blocks as such, hence urunit
+		// fails to mount them, unless it knows that they are read-only.
+		for _, b := range l.Blk {
+			if b.ID != "rootfs" && b.ReadOnly && !slices.Contains(l.UrunitFeatures, urunitReadOnlyFeature) {
+				return fmt.Errorf("block %s is read-only, but urunit does not support read-only mounts", b.ID)
+			}
+		}
 		err := l.setupUrunitConfig(data.Rootfs)
 		if err != nil {
 			return err
@@ -236,6 +273,12 @@ func (l *Linux) Init(data types.UnikernelParams) error {
 	return nil
 }
 
+// UsesUrunit returns true if the init process in the given command line of a
+// Linux guest is urunit.
+func UsesUrunit(cmdLine []string) bool {
+	return len(cmdLine) > 0 && strings.Contains(cmdLine[0], "urunit")
+}
+
 // parseCmdLine extracts the application and command from command line arguments.
 // Multi-word arguments are wrapped in single quotes for urunit compatibility.
 func (l *Linux) parseCmdLine(cmdLine []string) error {
@@ -323,14 +366,16 @@ func (l *Linux) buildUrunitConfig() string {
 			continue
 		}
 		sb.WriteString("ID:")
-		if l.Monitor == "firecracker" {
-			sb.WriteString("FC")
-		}
-		sb.WriteString(b.ID)
+		sb.WriteString(monitorBlockID(l.Monitor, b.ID))
 		sb.WriteString("\n")
 		sb.WriteString("MP:")
 		sb.WriteString(b.MountPoint)
 		sb.WriteString("\n")
+		// Only read-only blocks get the option, keeping the config of
+		// the rest as it was for versions of urunit without it.
+		if b.ReadOnly && slices.Contains(l.UrunitFeatures, urunitReadOnlyFeature) {
+			sb.WriteString("RO:1\n")
+		}
 	}
 	sb.WriteString(blkEndMarker)
 	sb.WriteString("\n")
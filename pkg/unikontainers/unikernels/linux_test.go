@@ -0,0 +1,121 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestLinuxReadOnly(t *testing.T) {
+	t.Parallel()
+	blocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/dev/dm-1", MountPoint: "/", ReadOnly: true},
+		{ID: "vol1", Source: "/data.img", MountPoint: "/data", ReadOnly: true},
+		{ID: "vol2", Source: "/logs.img", MountPoint: "/logs"},
+	}
+
+	t.Run("read-only block rootfs and volumes", func(t *testing.T) {
+		t.Parallel()
+		l := newLinux()
+		l.Blk = blocks
+		l.Monitor = "qemu"
+		l.RootFsType = "block"
+		l.RootFsRO = true
+		l.UrunitFeatures = []string{"ro"}
+
+		cmd, err := l.CommandString()
+		require.NoError(t, err)
+		assert.Contains(t, cmd, "root=/dev/vda ro")
+
+		blkArgs := l.MonitorBlockCli()
+		require.Len(t, blkArgs, 3)
+		assert.Contains(t, blkArgs[0].ExactArgs, "file=/dev/dm-1,readonly=on")
+		assert.Contains(t, blkArgs[1].ExactArgs, "file=/data.img,readonly=on")
+		assert.NotContains(t, blkArgs[2].ExactArgs, "readonly")
+
+		assert.Contains(t, l.buildUrunitConfig(),
+			"UBS\nID:vol1\nMP:/data\nRO:1\nID:vol2\nMP:/logs\nUBE\n")
+	})
+
+	t.Run("read-only volumes require the support of urunit", func(t *testing.T) {
+		t.Parallel()
+		params := types.UnikernelParams{
+			CmdLine: []string{"/urunit", "/bin/app"},
+			Monitor: "qemu",
+			Block:   blocks,
+			Rootfs:  types.RootfsParams{Type: "block", MonRootfs: t.TempDir(), ReadOnly: true},
+		}
+		err := newLinux().Init(params)
+		assert.ErrorContains(t, err, "vol1")
+
+		params.UrunitFeatures = []string{"ro"}
+		l := newLinux()
+		require.NoError(t, l.Init(params))
+		data, err := os.ReadFile(filepath.Join(params.Rootfs.MonRootfs, urunitConfPath))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "ID:vol1\nMP:/data\nRO:1\n")
+
+		// Without urunit, the guest does not mount the volumes itself
+		params.CmdLine = []string{"/bin/app"}
+		params.UrunitFeatures = nil
+		assert.NoError(t, newLinux().Init(params))
+	})
+
+	t.Run("read-only shared rootfs", func(t *testing.T) {
+		t.Parallel()
+		l := newLinux()
+		l.Monitor = "firecracker"
+		l.RootFsType = "virtiofs"
+		l.RootFsRO = true
+
+		cmd, err := l.CommandString()
+		require.NoError(t, err)
+		assert.Contains(t, cmd, "root=fs0 ro rootfstype=virtiofs")
+	})
+
+	t.Run("read-only blocks on firecracker", func(t *testing.T) {
+		t.Parallel()
+		l := newLinux()
+		l.Blk = blocks
+		l.Monitor = "firecracker"
+
+		assert.Equal(t, []types.MonitorBlockArgs{
+			{ID: "FCrootfs", Path: "/dev/dm-1", ReadOnly: true},
+			{ID: "FCvol1", Path: "/data.img", ReadOnly: true},
+			{ID: "FCvol2", Path: "/logs.img"},
+		}, l.MonitorBlockCli())
+	})
+}
+
+func TestLinuxDiskFormat(t *testing.T) {
+	t.Parallel()
+	l := newLinux()
+	l.Monitor = "qemu"
+	l.Blk = []types.BlockDevParams{
+		{ID: "rootfs", Source: "/dev/dm-1"},
+		{ID: "vol1", Source: "/data.qcow2", Format: "qcow2"},
+	}
+
+	blkArgs := l.MonitorBlockCli()
+	require.Len(t, blkArgs, 2)
+	assert.Contains(t, blkArgs[0].ExactArgs, "-drive format=raw,if=none,id=rootfs")
+	assert.Contains(t, blkArgs[1].ExactArgs, "-drive format=qcow2,if=none,id=vol1")
+}
@@ -44,6 +44,10 @@ func (m *Mewz) SupportsBlock() bool {
 	return false
 }
 
+func (m *Mewz) SupportedMonitors() []string {
+	return []string{"qemu"}
+}
+
 func (m *Mewz) SupportsFS(_ string) bool {
 	return false
 }
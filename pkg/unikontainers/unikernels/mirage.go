@@ -24,10 +24,11 @@ import (
 const MirageUnikernel string = "mirage"
 
 type Mirage struct {
-	Command string
-	Monitor string
-	Net     MirageNet
-	Block   []MirageBlock
+	Command   string
+	Monitor   string
+	Net       MirageNet
+	Block     []MirageBlock
+	Solo5Devs types.Solo5Devices
 }
 
 type MirageNet struct {
@@ -38,6 +39,7 @@ type MirageNet struct {
 type MirageBlock struct {
 	ID       string
 	HostPath string
+	ReadOnly bool
 }
 
 func (m *Mirage) CommandString() (string, error) {
@@ -50,15 +52,32 @@ func (m *Mirage) SupportsBlock() bool {
 	return true
 }
 
+// MirageOS mounts the block devices that Solo5 attaches to it, which are the
+// ones in its manifest or, without a manifest, a single one. Over Qemu, urunc
+// does not attach any block device to MirageOS.
+func (m *Mirage) MaxBlockMounts(monitor string, solo5Devs types.Solo5Devices) int {
+	switch monitor {
+	case "hvt", "spt", "sandbox":
+		return max(len(solo5Devs.Block), 1)
+	default:
+		return 0
+	}
+}
+
+func (m *Mirage) SupportedMonitors() []string {
+	return []string{"qemu", "hvt", "spt", "sandbox"}
+}
+
 func (m *Mirage) SupportsFS(_ string) bool {
 	return false
 }
 
 func (m *Mirage) MonitorNetCli(ifName string, mac string) string {
 	switch m.Monitor {
-	case "hvt", "spt":
-		netOption := "--net:service=" + ifName
-		netOption += " --net-mac:service=" + mac
+	case "hvt", "spt", "sandbox":
+		netName := solo5NetName(m.Solo5Devs.Net, "service")
+		netOption := "--net:" + netName + "=" + ifName
+		netOption += " --net-mac:" + netName + "=" + mac
 		return netOption
 	default:
 		return ""
@@ -70,23 +89,27 @@ func (m *Mirage) MonitorBlockCli() []types.MonitorBlockArgs {
 		return nil
 	}
 	switch m.Monitor {
-	case "hvt", "spt":
-		// TODO: Explore options for multiple block devices in MirageOS
-		// over Solo5-spt and Solo5-hvt. Solo5 expects to use as an ID
-		// a specific name which the guest is also aware of in order to
-		// attach the respective block. As a result, urunc needs to know
-		// the correct ID to set, which is not straightforward. Therefore,
-		// there are two options. Either we read the Solo5 manifest or,
-		// we require specific IDs. Till we decide about that, we will
-		// use a single block device. We also need to find some use cases
-		// where multiple block devices are configured in MirageOS and check
-		// how MirageOS handles/configures them.
-		return []types.MonitorBlockArgs{
-			{
-				ID:   "storage",
-				Path: m.Block[0].HostPath,
-			},
+	case "hvt", "spt", "sandbox":
+		// Solo5 attaches a block device only if the guest declares
+		// its name in the manifest. Without the manifest, we use a
+		// single block device with the name that MirageOS uses by
+		// default.
+		ids := make([]string, len(m.Block))
+		for i, aBlock := range m.Block {
+			ids[i] = aBlock.ID
+		}
+		var blkArgs []types.MonitorBlockArgs
+		for i, name := range solo5BlockNames(m.Solo5Devs.Block, ids, "storage") {
+			if name == "" {
+				continue
+			}
+			blkArgs = append(blkArgs, types.MonitorBlockArgs{
+				ID:       name,
+				Path:     m.Block[i].HostPath,
+				ReadOnly: m.Block[i].ReadOnly,
+			})
 		}
+		return blkArgs
 	default:
 		return nil
 	}
@@ -107,10 +130,12 @@ func (m *Mirage) Init(data types.UnikernelParams) error {
 		newBlk := MirageBlock{
 			ID:       blk.ID,
 			HostPath: blk.Source,
+			ReadOnly: blk.ReadOnly,
 		}
 		m.Block = append(m.Block, newBlk)
 	}
 
+	m.Solo5Devs = data.Solo5Devs
 	m.Command = strings.Join(data.CmdLine, " ")
 	m.Monitor = data.Monitor
 
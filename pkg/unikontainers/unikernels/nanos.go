@@ -0,0 +1,168 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const NanosUnikernel string = "nanos"
+
+type Nanos struct {
+	Monitor  string
+	Manifest NanosManifest
+	Blk      []types.BlockDevParams
+}
+
+// NanosManifest holds the attributes of the root tuple of the Nanos
+// manifest that urunc sets for the guest. They override the respective
+// attributes of the manifest in the image of the guest.
+type NanosManifest struct {
+	Arguments   []string
+	Environment []string // In the KEY=VALUE format
+	IPAddr      string
+	Netmask     string
+	Gateway     string
+	Mounts      map[string]string // Volume to mount point
+}
+
+// CommandString returns the command line of the Nanos kernel. Nanos
+// applies every option of its command line in the key=value format to the
+// root tuple of its manifest, where the key is the dot-separated path of
+// the attribute. The elements of vectors (e.g. arguments) are addressed
+// by their index.
+func (n *Nanos) CommandString() (string, error) {
+	var options []string
+	for i, arg := range n.Manifest.Arguments {
+		options = append(options, "arguments."+strconv.Itoa(i)+"="+arg)
+	}
+	for _, env := range n.Manifest.Environment {
+		key, value, found := strings.Cut(env, "=")
+		if !found || key == "" {
+			continue
+		}
+		options = append(options, "environment."+key+"="+value)
+	}
+	if n.Manifest.IPAddr != "" {
+		options = append(options, "ipaddr="+n.Manifest.IPAddr)
+		options = append(options, "netmask="+n.Manifest.Netmask)
+		options = append(options, "gateway="+n.Manifest.Gateway)
+	}
+	for _, volume := range slices.Sorted(maps.Keys(n.Manifest.Mounts)) {
+		options = append(options, "mounts."+volume+"="+n.Manifest.Mounts[volume])
+	}
+
+	for _, option := range options {
+		if strings.ContainsAny(option, " \t\n") {
+			return "", fmt.Errorf("nanos command line option %q contains whitespace", option)
+		}
+	}
+
+	return strings.Join(options, " "), nil
+}
+
+func (n *Nanos) SupportsBlock() bool {
+	return true
+}
+
+func (n *Nanos) SupportedMonitors() []string {
+	return []string{"qemu", "firecracker"}
+}
+
+// Nanos can use only its own filesystem (TFS) as a rootfs, which comes from
+// a block image. However, it can mount shared directories through virtiofs.
+func (n *Nanos) SupportsFS(fsType string) bool {
+	switch fsType {
+	case "virtiofs":
+		return true
+	default:
+		return false
+	}
+}
+
+// The default virtio-net devices of the monitors are sufficient for Nanos.
+func (n *Nanos) MonitorNetCli(_ string, _ string) string {
+	return ""
+}
+
+func (n *Nanos) MonitorBlockCli() []types.MonitorBlockArgs {
+	if len(n.Blk) == 0 {
+		return nil
+	}
+	switch n.Monitor {
+	case "qemu", "firecracker":
+		blkArgs := make([]types.MonitorBlockArgs, 0, len(n.Blk))
+		for _, aBlock := range n.Blk {
+			blkArgs = append(blkArgs, types.MonitorBlockArgs{
+				ID:       monitorBlockID(n.Monitor, aBlock.ID),
+				Path:     aBlock.Source,
+				ReadOnly: aBlock.ReadOnly,
+				Format:   aBlock.Format,
+			})
+		}
+		return blkArgs
+	default:
+		return nil
+	}
+}
+
+func (n *Nanos) MonitorCli() types.MonitorCliArgs {
+	switch n.Monitor {
+	case "qemu":
+		extraCliArgs := types.MonitorCliArgs{
+			OtherArgs: " -no-reboot",
+		}
+		// On x86, Nanos reports its exit code through the isa-debug-exit device
+		if runtime.GOARCH == "amd64" {
+			extraCliArgs.OtherArgs += " -device isa-debug-exit"
+		}
+		return extraCliArgs
+	default:
+		return types.MonitorCliArgs{}
+	}
+}
+
+func (n *Nanos) Init(data types.UnikernelParams) error {
+	n.Monitor = data.Monitor
+	n.Blk = data.Block
+	n.Manifest = NanosManifest{
+		Arguments:   data.CmdLine,
+		Environment: data.EnvVars,
+	}
+
+	if data.Net.IP != "" {
+		n.Manifest.IPAddr = data.Net.IP
+		n.Manifest.Netmask = data.Net.Mask
+		n.Manifest.Gateway = data.Net.Gateway
+	}
+
+	if data.Rootfs.Type == "virtiofs" {
+		n.Manifest.Mounts = map[string]string{"fs0": "/"}
+	}
+
+	return nil
+}
+
+func newNanos() *Nanos {
+	nanosStruct := new(Nanos)
+	return nanosStruct
+}
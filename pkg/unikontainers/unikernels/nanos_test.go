@@ -0,0 +1,115 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestNanosCommandString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("network, env and args", func(t *testing.T) {
+		t.Parallel()
+		n := newNanos()
+		err := n.Init(types.UnikernelParams{
+			CmdLine: []string{"/hello", "--port=8080"},
+			EnvVars: []string{"PATH=/usr/bin:/bin", "EMPTY=", "INVALID", "=novalue"},
+			Monitor: "qemu",
+			Net: types.NetDevParams{
+				IP:      "10.0.0.2",
+				Mask:    "255.255.255.0",
+				Gateway: "10.0.0.1",
+			},
+			Rootfs: types.RootfsParams{Type: "block"},
+		})
+		require.NoError(t, err)
+
+		cmd, err := n.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "arguments.0=/hello arguments.1=--port=8080"+
+			" environment.PATH=/usr/bin:/bin environment.EMPTY="+
+			" ipaddr=10.0.0.2 netmask=255.255.255.0 gateway=10.0.0.1", cmd)
+	})
+
+	t.Run("virtiofs rootfs without network", func(t *testing.T) {
+		t.Parallel()
+		n := newNanos()
+		err := n.Init(types.UnikernelParams{
+			CmdLine: []string{"/node", "app.js"},
+			Monitor: "firecracker",
+			Rootfs:  types.RootfsParams{Type: "virtiofs"},
+		})
+		require.NoError(t, err)
+
+		cmd, err := n.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "arguments.0=/node arguments.1=app.js mounts.fs0=/", cmd)
+	})
+
+	t.Run("values with whitespace", func(t *testing.T) {
+		t.Parallel()
+		for _, params := range []types.UnikernelParams{
+			{CmdLine: []string{"/hello", "hello world"}},
+			{CmdLine: []string{"/hello"}, EnvVars: []string{"MSG=a\tb"}},
+		} {
+			n := newNanos()
+			require.NoError(t, n.Init(params))
+
+			_, err := n.CommandString()
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestNanosMonitorArgs(t *testing.T) {
+	t.Parallel()
+	blocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/dev/dm-1", Format: "raw"},
+		{ID: "vol0", Source: "/data.img", ReadOnly: true, Format: "raw"},
+	}
+
+	n := &Nanos{Monitor: "qemu", Blk: blocks}
+	assert.Equal(t, []types.MonitorBlockArgs{
+		{ID: "rootfs", Path: "/dev/dm-1", Format: "raw"},
+		{ID: "vol0", Path: "/data.img", ReadOnly: true, Format: "raw"},
+	}, n.MonitorBlockCli())
+	cli := n.MonitorCli()
+	assert.Contains(t, cli.OtherArgs, "-no-reboot")
+	if runtime.GOARCH == "amd64" {
+		assert.Contains(t, cli.OtherArgs, "-device isa-debug-exit")
+	}
+
+	n = &Nanos{Monitor: "firecracker", Blk: blocks}
+	assert.Equal(t, []types.MonitorBlockArgs{
+		{ID: "FCrootfs", Path: "/dev/dm-1", Format: "raw"},
+		{ID: "FCvol0", Path: "/data.img", ReadOnly: true, Format: "raw"},
+	}, n.MonitorBlockCli())
+	assert.Equal(t, types.MonitorCliArgs{}, n.MonitorCli())
+
+	n = &Nanos{Monitor: "qemu"}
+	assert.Nil(t, n.MonitorBlockCli())
+
+	assert.True(t, n.SupportsBlock())
+	assert.True(t, n.SupportsFS("virtiofs"))
+	for _, fsType := range []string{"ext4", "9pfs", "initrd"} {
+		assert.False(t, n.SupportsFS(fsType), fsType)
+	}
+}
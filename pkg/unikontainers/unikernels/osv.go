@@ -0,0 +1,164 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+const OSvUnikernel string = "osv"
+
+type OSv struct {
+	Monitor string
+	Command []string
+	Env     []string
+	Net     OSvNet
+	RootFS  string
+	Blk     []types.BlockDevParams
+}
+
+type OSvNet struct {
+	Address string
+	Mask    string
+	Gateway string
+}
+
+// CommandString returns the command line of the OSv kernel. It consists of
+// the options of the OSv loader (e.g. --ip, --env), followed by the command
+// of the application. The loader of OSv splits its command line in words
+// like a shell, hence any argument with special characters gets quoted.
+func (o *OSv) CommandString() (string, error) {
+	if len(o.Command) == 0 {
+		return "", fmt.Errorf("no command was specified")
+	}
+
+	var options []string
+	if o.Net.Address != "" {
+		options = append(options, "--ip=eth0,"+o.Net.Address+","+o.Net.Mask)
+		options = append(options, "--defaultgw="+o.Net.Gateway)
+	}
+	for _, env := range o.Env {
+		key, _, found := strings.Cut(env, "=")
+		if !found || key == "" {
+			continue
+		}
+		options = append(options, osvQuote("--env="+env))
+	}
+	if o.RootFS != "" {
+		options = append(options, "--rootfs="+o.RootFS)
+	}
+	for _, arg := range o.Command {
+		options = append(options, osvQuote(arg))
+	}
+
+	return strings.Join(options, " "), nil
+}
+
+// osvQuote wraps a word of the OSv command line in double quotes, if it
+// contains whitespace or any character that the loader of OSv treats
+// specially. The loader separates commands with ';' and '&'.
+func osvQuote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n\"'\\;&") {
+		return word
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + escaper.Replace(word) + `"`
+}
+
+func (o *OSv) SupportsBlock() bool {
+	return true
+}
+
+func (o *OSv) SupportedMonitors() []string {
+	return []string{"qemu", "firecracker"}
+}
+
+// OSv can boot from a ZFS or a read-only (ROFS) image attached as a block
+// device, or from a directory shared through virtiofs.
+func (o *OSv) SupportsFS(fsType string) bool {
+	switch fsType {
+	case "zfs", "rofs", "virtiofs":
+		return true
+	default:
+		return false
+	}
+}
+
+// The default virtio-net devices of the monitors are sufficient for OSv.
+func (o *OSv) MonitorNetCli(_ string, _ string) string {
+	return ""
+}
+
+func (o *OSv) MonitorBlockCli() []types.MonitorBlockArgs {
+	if len(o.Blk) == 0 {
+		return nil
+	}
+	switch o.Monitor {
+	case "qemu", "firecracker":
+		blkArgs := make([]types.MonitorBlockArgs, 0, len(o.Blk))
+		for _, aBlock := range o.Blk {
+			blkArgs = append(blkArgs, types.MonitorBlockArgs{
+				ID:       monitorBlockID(o.Monitor, aBlock.ID),
+				Path:     aBlock.Source,
+				ReadOnly: aBlock.ReadOnly,
+				Format:   aBlock.Format,
+			})
+		}
+		return blkArgs
+	default:
+		return nil
+	}
+}
+
+func (o *OSv) MonitorCli() types.MonitorCliArgs {
+	switch o.Monitor {
+	case "qemu":
+		return types.MonitorCliArgs{
+			OtherArgs: " -no-reboot",
+		}
+	default:
+		return types.MonitorCliArgs{}
+	}
+}
+
+func (o *OSv) Init(data types.UnikernelParams) error {
+	o.Monitor = data.Monitor
+	o.Command = data.CmdLine
+	o.Env = data.EnvVars
+	o.Blk = data.Block
+
+	if data.Net.IP != "" {
+		o.Net.Address = data.Net.IP
+		o.Net.Mask = data.Net.Mask
+		o.Net.Gateway = data.Net.Gateway
+	}
+
+	// OSv detects by itself if the root block device contains a ZFS or
+	// a ROFS filesystem, but it needs to be told to mount virtiofs.
+	if data.Rootfs.Type == "virtiofs" {
+		o.RootFS = "virtiofs"
+	}
+
+	return nil
+}
+
+func newOSv() *OSv {
+	osvStruct := new(OSv)
+	return osvStruct
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestOSvQuote(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"/hello":           "/hello",
+		"--port=8080":      "--port=8080",
+		"hello world":      `"hello world"`,
+		"":                 `""`,
+		"a;b":              `"a;b"`,
+		"a&b":              `"a&b"`,
+		`say "hi"`:         `"say \"hi\""`,
+		`C:\path`:          `"C:\\path"`,
+		"it's":             `"it's"`,
+		"--env=MSG=a b c":  `"--env=MSG=a b c"`,
+		"--env=EMPTY=":     "--env=EMPTY=",
+		"tab\tseparated":   "\"tab\tseparated\"",
+		"new\nline":        "\"new\nline\"",
+		"--env=PATH=/bin:": "--env=PATH=/bin:",
+	}
+	for word, expected := range tests {
+		assert.Equal(t, expected, osvQuote(word), word)
+	}
+}
+
+func TestOSvCommandString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("network, env and block rootfs", func(t *testing.T) {
+		t.Parallel()
+		o := newOSv()
+		err := o.Init(types.UnikernelParams{
+			CmdLine: []string{"/hello", "--greeting", "hello world"},
+			EnvVars: []string{"PATH=/usr/bin:/bin", "MSG=a \"quoted\" value", "EMPTY=", "INVALID", "=novalue"},
+			Monitor: "qemu",
+			Net: types.NetDevParams{
+				IP:      "10.0.0.2",
+				Mask:    "255.255.255.0",
+				Gateway: "10.0.0.1",
+			},
+			Rootfs: types.RootfsParams{Type: "block"},
+		})
+		require.NoError(t, err)
+
+		cmd, err := o.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "--ip=eth0,10.0.0.2,255.255.255.0 --defaultgw=10.0.0.1"+
+			" --env=PATH=/usr/bin:/bin"+
+			` "--env=MSG=a \"quoted\" value"`+
+			" --env=EMPTY="+
+			` /hello --greeting "hello world"`, cmd)
+	})
+
+	t.Run("virtiofs rootfs without network", func(t *testing.T) {
+		t.Parallel()
+		o := newOSv()
+		err := o.Init(types.UnikernelParams{
+			CmdLine: []string{"/usr/bin/node", "app.js"},
+			Monitor: "firecracker",
+			Rootfs:  types.RootfsParams{Type: "virtiofs"},
+		})
+		require.NoError(t, err)
+
+		cmd, err := o.CommandString()
+		require.NoError(t, err)
+		assert.Equal(t, "--rootfs=virtiofs /usr/bin/node app.js", cmd)
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		t.Parallel()
+		o := newOSv()
+		require.NoError(t, o.Init(types.UnikernelParams{Monitor: "qemu"}))
+
+		_, err := o.CommandString()
+		assert.Error(t, err)
+	})
+}
+
+func TestOSvMonitorArgs(t *testing.T) {
+	t.Parallel()
+	blocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/dev/dm-1"},
+		{ID: "vol0", Source: "/data.img"},
+	}
+
+	o := &OSv{Monitor: "qemu", Blk: blocks}
+	assert.Equal(t, []types.MonitorBlockArgs{
+		{ID: "rootfs", Path: "/dev/dm-1"},
+		{ID: "vol0", Path: "/data.img"},
+	}, o.MonitorBlockCli())
+	assert.Equal(t, " -no-reboot", o.MonitorCli().OtherArgs)
+
+	o = &OSv{Monitor: "firecracker", Blk: blocks}
+	assert.Equal(t, []types.MonitorBlockArgs{
+		{ID: "FCrootfs", Path: "/dev/dm-1"},
+		{ID: "FCvol0", Path: "/data.img"},
+	}, o.MonitorBlockCli())
+	assert.Equal(t, types.MonitorCliArgs{}, o.MonitorCli())
+
+	o = &OSv{Monitor: "hvt", Blk: blocks}
+	assert.Nil(t, o.MonitorBlockCli())
+
+	assert.True(t, o.SupportsBlock())
+	for _, fsType := range []string{"zfs", "rofs", "virtiofs"} {
+		assert.True(t, o.SupportsFS(fsType), fsType)
+	}
+	for _, fsType := range []string{"ext4", "9pfs", "initrd"} {
+		assert.False(t, o.SupportsFS(fsType), fsType)
+	}
+}
@@ -26,11 +26,13 @@ const RumprunUnikernel string = "rumprun"
 const SubnetMask125 = "128.0.0.0"
 
 type Rumprun struct {
-	Command string
-	Monitor string
-	Envs    []string
-	Net     RumprunNet
-	Blk     RumprunBlk
+	Command   string
+	Monitor   string
+	Envs      []string
+	Net       RumprunNet
+	Blk       RumprunBlk
+	Devices   []types.BlockDevParams // All the block devices of the guest
+	Solo5Devs types.Solo5Devices
 }
 
 type RumprunCmd struct {
@@ -128,6 +130,15 @@ func (r *Rumprun) SupportsBlock() bool {
 	return true
 }
 
+func (r *Rumprun) SupportedMonitors() []string {
+	return []string{"hvt", "spt", "sandbox", "hedge"}
+}
+
+// Rumprun mounts only the first block device of the guest.
+func (r *Rumprun) MaxBlockMounts(_ string, _ types.Solo5Devices) int {
+	return 1
+}
+
 func (r *Rumprun) SupportsFS(fsType string) bool {
 	switch fsType {
 	case "ext2":
@@ -139,9 +150,10 @@ func (r *Rumprun) SupportsFS(fsType string) bool {
 
 func (r *Rumprun) MonitorNetCli(ifName string, mac string) string {
 	switch r.Monitor {
-	case "hvt", "spt":
-		netOption := "--net:tap=" + ifName
-		netOption += " --net-mac:tap=" + mac
+	case "hvt", "spt", "sandbox":
+		netName := solo5NetName(r.Solo5Devs.Net, "tap")
+		netOption := "--net:" + netName + "=" + ifName
+		netOption += " --net-mac:" + netName + "=" + mac
 		return netOption
 	default:
 		return ""
@@ -150,15 +162,28 @@ func (r *Rumprun) MonitorNetCli(ifName string, mac string) string {
 
 func (r *Rumprun) MonitorBlockCli() []types.MonitorBlockArgs {
 	switch r.Monitor {
-	case "hvt", "spt":
-		// TODO: Explore options for multiple block devices in Rumprun
-		// over Solo5-spt and Solo5-hvt. Solo5 expects to use as an ID
-		// a specific name which the guest is also aware of in order to
-		// attach the respective block. As a result, urunc needs to know
-		// the correct ID to set, which is not straightforward. Therefore,
-		// there are two options. Either we read the Solo5 manifest or,
-		// we require specific IDs. Till we decide about that, we will
-		// use a single block device only for the rootfs of Rumprun.
+	case "hvt", "spt", "sandbox":
+		// Solo5 attaches a block device only if the guest declares
+		// its name in the manifest. Without the manifest, we use a
+		// single block device for the rootfs of Rumprun.
+		ids := make([]string, len(r.Devices))
+		for i, aBlock := range r.Devices {
+			ids[i] = aBlock.ID
+		}
+		var blkArgs []types.MonitorBlockArgs
+		for i, name := range solo5BlockNames(r.Solo5Devs.Block, ids, "rootfs") {
+			if name == "" {
+				continue
+			}
+			blkArgs = append(blkArgs, types.MonitorBlockArgs{
+				ID:       name,
+				Path:     r.Devices[i].Source,
+				ReadOnly: r.Devices[i].ReadOnly,
+				Format:   r.Devices[i].Format,
+			})
+		}
+		return blkArgs
+	case "hedge":
 		return []types.MonitorBlockArgs{
 			{
 				ID:   "rootfs",
@@ -215,6 +240,8 @@ func (r *Rumprun) Init(data types.UnikernelParams) error {
 		r.Blk.Source = ""
 	}
 
+	r.Devices = data.Block
+	r.Solo5Devs = data.Solo5Devs
 	r.Command = strings.Join(data.CmdLine, " ")
 	r.Monitor = data.Monitor
 	r.Envs = data.EnvVars
@@ -0,0 +1,65 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"slices"
+)
+
+// solo5BlockNames returns the name of the Solo5 device, where each one of
+// the block devices with the given IDs gets attached. Solo5 attaches a
+// device only if its name is declared in the manifest of the unikernel.
+// Therefore, a block device gets attached to the declared device with the
+// same name as its ID, or otherwise to the first declared device which is
+// still available. The name is empty for the block devices which do not
+// fit in the declared devices. If the manifest is unknown, only the first
+// block device gets attached with the given default name.
+func solo5BlockNames(declared []string, ids []string, defaultName string) []string {
+	names := make([]string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+	if len(declared) == 0 {
+		names[0] = defaultName
+		return names
+	}
+
+	available := slices.Clone(declared)
+	for i, id := range ids {
+		if idx := slices.Index(available, id); id != "" && idx >= 0 {
+			names[i] = id
+			available = slices.Delete(available, idx, idx+1)
+		}
+	}
+	for i := range ids {
+		if names[i] != "" || len(available) == 0 {
+			continue
+		}
+		names[i] = available[0]
+		available = available[1:]
+	}
+
+	return names
+}
+
+// solo5NetName returns the name of the network device of a Solo5 based
+// unikernel, which is the first network device declared in its manifest.
+func solo5NetName(declared []string, defaultName string) string {
+	if len(declared) == 0 {
+		return defaultName
+	}
+
+	return declared[0]
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestSolo5BlockNames(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		declared []string
+		ids      []string
+		expected []string
+	}{
+		{
+			name:     "no block devices",
+			declared: []string{"storage"},
+			ids:      nil,
+			expected: []string{},
+		},
+		{
+			name:     "unknown manifest",
+			declared: nil,
+			ids:      []string{"rootfs", "vol0"},
+			expected: []string{"default", ""},
+		},
+		{
+			name:     "matching IDs first",
+			declared: []string{"storage", "rootfs"},
+			ids:      []string{"vol0", "rootfs"},
+			expected: []string{"storage", "rootfs"},
+		},
+		{
+			name:     "in order of declaration",
+			declared: []string{"storage", "data"},
+			ids:      []string{"rootfs", "vol1"},
+			expected: []string{"storage", "data"},
+		},
+		{
+			name:     "more devices than declared",
+			declared: []string{"storage"},
+			ids:      []string{"rootfs", "vol1", "annot_vol"},
+			expected: []string{"storage", "", ""},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, solo5BlockNames(tc.declared, tc.ids, "default"))
+		})
+	}
+}
+
+func TestSolo5DeviceNames(t *testing.T) {
+	t.Parallel()
+	blocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/dev/dm-1"},
+		{ID: "vol1", Source: "/dev/sdb"},
+	}
+
+	t.Run("mirage with manifest", func(t *testing.T) {
+		t.Parallel()
+		m := newMirage()
+		require.NoError(t, m.Init(types.UnikernelParams{
+			Monitor:   "hvt",
+			Block:     blocks,
+			Solo5Devs: types.Solo5Devices{Block: []string{"storage", "data"}, Net: []string{"net0"}},
+		}))
+		assert.Equal(t, []types.MonitorBlockArgs{
+			{ID: "storage", Path: "/dev/dm-1"},
+			{ID: "data", Path: "/dev/sdb"},
+		}, m.MonitorBlockCli())
+		assert.Equal(t, "--net:net0=tap0 --net-mac:net0=aa:bb:cc:dd:ee:ff", m.MonitorNetCli("tap0", "aa:bb:cc:dd:ee:ff"))
+	})
+
+	t.Run("mirage without manifest", func(t *testing.T) {
+		t.Parallel()
+		m := newMirage()
+		require.NoError(t, m.Init(types.UnikernelParams{Monitor: "spt", Block: blocks}))
+		assert.Equal(t, []types.MonitorBlockArgs{{ID: "storage", Path: "/dev/dm-1"}}, m.MonitorBlockCli())
+		assert.Equal(t, "--net:service=tap0 --net-mac:service=aa:bb:cc:dd:ee:ff", m.MonitorNetCli("tap0", "aa:bb:cc:dd:ee:ff"))
+	})
+
+	t.Run("rumprun with manifest", func(t *testing.T) {
+		t.Parallel()
+		r := newRumprun()
+		require.NoError(t, r.Init(types.UnikernelParams{
+			Monitor:   "hvt",
+			Block:     blocks,
+			Solo5Devs: types.Solo5Devices{Block: []string{"rootfs"}, Net: []string{"tap"}},
+		}))
+		assert.Equal(t, []types.MonitorBlockArgs{{ID: "rootfs", Path: "/dev/dm-1"}}, r.MonitorBlockCli())
+		assert.Equal(t, "--net:tap=tap0 --net-mac:tap=aa:bb:cc:dd:ee:ff", r.MonitorNetCli("tap0", "aa:bb:cc:dd:ee:ff"))
+	})
+}
@@ -16,19 +16,50 @@ package unikernels
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
 )
 
 var ErrNotSupportedUnikernel = errors.New("unikernel is not supported")
 
-func New(unikernelType string) (types.Unikernel, error) {
+// SupportsMonitor returns true if the given unikernel type can run on top
+// of the given monitor.
+func SupportsMonitor(unikernelType string, monitor string) bool {
+	unikernel, err := New(unikernelType, "")
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(unikernel.SupportedMonitors(), monitor)
+}
+
+// HandlesPowerOff returns true if the guests of the given unikernel type
+// power off when the given monitor asks them to. QEMU, cloud-hypervisor and
+// crosvm press the ACPI power button and Firecracker sends a Ctrl+Alt+Del,
+// which most unikernels ignore. The Solo5 tenders exit on SIGTERM on their
+// own, regardless of the guest.
+func HandlesPowerOff(unikernelType string, monitor string) bool {
+	switch monitor {
+	case "qemu", "cloud-hypervisor", "crosvm":
+		return slices.Contains([]string{LinuxUnikernel, NanosUnikernel, OSvUnikernel}, unikernelType)
+	case "firecracker":
+		return unikernelType == LinuxUnikernel
+	default:
+		return true
+	}
+}
+
+// New returns a unikernel of the given type. Only the unikernels whose
+// features depend on their version (e.g. Unikraft) use the given version,
+// which can be empty if it is unknown.
+func New(unikernelType string, version string) (types.Unikernel, error) {
 	switch unikernelType {
 	case RumprunUnikernel:
 		unikernel := newRumprun()
 		return unikernel, nil
 	case UnikraftUnikernel:
-		unikernel := newUnikraft()
+		unikernel := newUnikraft(version)
 		return unikernel, nil
 	case MirageUnikernel:
 		unikernel := newMirage()
@@ -39,6 +70,15 @@ func New(unikernelType string) (types.Unikernel, error) {
 	case LinuxUnikernel:
 		unikernel := newLinux()
 		return unikernel, nil
+	case NanosUnikernel:
+		unikernel := newNanos()
+		return unikernel, nil
+	case OSvUnikernel:
+		unikernel := newOSv()
+		return unikernel, nil
+	case HermitUnikernel:
+		unikernel := newHermit()
+		return unikernel, nil
 	default:
 		return nil, ErrNotSupportedUnikernel
 	}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+var unikernelTypes = []string{
+	RumprunUnikernel,
+	UnikraftUnikernel,
+	MirageUnikernel,
+	MewzUnikernel,
+	LinuxUnikernel,
+	NanosUnikernel,
+	OSvUnikernel,
+	HermitUnikernel,
+}
+
+var monitors = []string{"qemu", "firecracker", "cloud-hypervisor", "crosvm", "hvt", "spt", "sandbox", "hedge"}
+
+// TestSupportedMonitors checks that the monitors which a unikernel declares
+// are consistent with the monitors that it configures.
+func TestSupportedMonitors(t *testing.T) {
+	t.Parallel()
+	params := types.UnikernelParams{
+		CmdLine: []string{"/app"},
+		Version: "0.18.0",
+		Net: types.NetDevParams{
+			IP:      "10.0.0.2",
+			Mask:    "255.255.255.0",
+			Gateway: "10.0.0.1",
+		},
+		Block: []types.BlockDevParams{
+			{ID: "vol0", Source: "/data.img", MountPoint: "/data"},
+		},
+	}
+
+	for _, unikernelType := range unikernelTypes {
+		t.Run(unikernelType, func(t *testing.T) {
+			t.Parallel()
+			unikernel, err := New(unikernelType, "")
+			require.NoError(t, err)
+			declared := unikernel.SupportedMonitors()
+			require.NotEmpty(t, declared)
+			for _, monitor := range declared {
+				assert.Contains(t, monitors, monitor)
+			}
+
+			for _, monitor := range monitors {
+				unikernel, err := New(unikernelType, params.Version)
+				require.NoError(t, err)
+				monParams := params
+				monParams.Monitor = monitor
+				_ = unikernel.Init(monParams)
+
+				configured := unikernel.MonitorNetCli("tap0_urunc", "aa:bb:cc:dd:ee:ff") != "" ||
+					len(unikernel.MonitorBlockCli()) > 0 ||
+					unikernel.MonitorCli() != types.MonitorCliArgs{}
+				supported := slices.Contains(declared, monitor)
+				assert.Equal(t, supported, SupportsMonitor(unikernelType, monitor), monitor)
+				if configured {
+					assert.True(t, supported, "%s configures %s, which it does not declare", unikernelType, monitor)
+				}
+			}
+		})
+	}
+
+	assert.False(t, SupportsMonitor("unknown", "qemu"))
+}
+
+func TestHandlesPowerOff(t *testing.T) {
+	t.Parallel()
+	for _, monitor := range []string{"qemu", "cloud-hypervisor", "crosvm"} {
+		for _, unikernelType := range []string{LinuxUnikernel, NanosUnikernel, OSvUnikernel} {
+			assert.True(t, HandlesPowerOff(unikernelType, monitor), "%s on %s", unikernelType, monitor)
+		}
+		for _, unikernelType := range []string{UnikraftUnikernel, MewzUnikernel, MirageUnikernel, RumprunUnikernel} {
+			assert.False(t, HandlesPowerOff(unikernelType, monitor), "%s on %s", unikernelType, monitor)
+		}
+	}
+
+	assert.True(t, HandlesPowerOff(LinuxUnikernel, "firecracker"))
+	assert.False(t, HandlesPowerOff(UnikraftUnikernel, "firecracker"))
+	for _, monitor := range []string{"hvt", "spt", "sandbox", "hedge"} {
+		assert.True(t, HandlesPowerOff(MirageUnikernel, monitor), monitor)
+	}
+}
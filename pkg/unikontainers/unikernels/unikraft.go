@@ -18,6 +18,8 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	version "github.com/hashicorp/go-version"
@@ -27,6 +29,9 @@ import (
 const UnikraftUnikernel string = "unikraft"
 const UnikraftCompatVersion string = "0.16.1"
 
+// The DNS server of Unikraft, if none gets configured for the container
+const defaultUnikraftDNS string = "8.8.8.8"
+
 var ErrUndefinedVersion = errors.New("version is undefined, using default version")
 var ErrVersionParsing = errors.New("failed to parse provided version, using default version")
 
@@ -38,6 +43,7 @@ type Unikraft struct {
 	Net     UnikraftNet
 	VFS     UnikraftVFS
 	Version string
+	Blk     []types.BlockDevParams
 }
 
 type UnikraftNet struct {
@@ -72,27 +78,84 @@ func (u *Unikraft) CommandString() (string, error) {
 		u.Command), nil
 }
 
+// Block devices get mounted through vfs.fstab and hence they are supported
+// only by the versions of Unikraft with vfs.fstab.
 func (u *Unikraft) SupportsBlock() bool {
-	return false
+	return u.supportsFstab()
+}
+
+func (u *Unikraft) SupportedMonitors() []string {
+	return []string{"qemu", "firecracker"}
 }
 
+// Unikraft does not have an fstab driver for any block filesystem (e.g.
+// ext4) by default and therefore, urunc does not mount the block devices
+// of the container (e.g. the container's rootfs) in the guest.
 func (u *Unikraft) SupportsFS(fsType string) bool {
 	switch fsType {
+	case "initrd":
+		return true
 	case "9pfs":
 		return true
+	case "virtiofs":
+		return u.supportsFstab()
 	default:
 		return false
 	}
 }
 
+// supportsFstab returns true if the version of Unikraft supports vfs.fstab.
+// As in configureUnikraftArgs, an unknown version is considered a recent
+// one.
+func (u *Unikraft) supportsFstab() bool {
+	compat, err := isCompatVersion(u.Version)
+	return err != nil || !compat
+}
+
+// isCompatVersion returns true if the given version of Unikraft is older
+// than UnikraftCompatVersion.
+func isCompatVersion(unikraftVersion string) (bool, error) {
+	if unikraftVersion == "" {
+		return false, ErrUndefinedVersion
+	}
+
+	unikernelVersion, err := version.NewVersion(unikraftVersion)
+	if err != nil {
+		return false, ErrVersionParsing
+	}
+
+	targetVersion, err := version.NewVersion(UnikraftCompatVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse default version: %w", err)
+	}
+
+	return unikernelVersion.LessThan(targetVersion), nil
+}
+
 // There is no need for any changes here yet.
 func (u *Unikraft) MonitorNetCli(_ string, _ string) string {
 	return ""
 }
 
-// We have not managed to make Unikraft run with block yet.
 func (u *Unikraft) MonitorBlockCli() []types.MonitorBlockArgs {
-	return nil
+	if len(u.Blk) == 0 {
+		return nil
+	}
+	switch u.Monitor {
+	case "qemu", "firecracker":
+		blkArgs := make([]types.MonitorBlockArgs, 0, len(u.Blk))
+		for _, aBlock := range u.Blk {
+			blkArgs = append(blkArgs, types.MonitorBlockArgs{
+				ID:       monitorBlockID(u.Monitor, aBlock.ID),
+				Path:     aBlock.Source,
+				ReadOnly: aBlock.ReadOnly,
+				Format:   aBlock.Format,
+			})
+		}
+		return blkArgs
+	default:
+		return nil
+	}
 }
 
 // There are no generic CLI hypervisor options for Unikraft yet.
@@ -106,56 +169,46 @@ func (u *Unikraft) Init(data types.UnikernelParams) error {
 	u.AppName = "Unikraft"
 	u.Monitor = data.Monitor
 	u.Command = strings.Join(data.CmdLine, " ")
+	u.Blk = data.Block
 
-	return u.configureUnikraftArgs(data.Rootfs.Type, data.Net.IP, data.Net.Gateway, data.Net.Mask)
+	return u.configureUnikraftArgs(data.Rootfs.Type, data.Net, data.Hostname)
 }
 
-func (u *Unikraft) configureUnikraftArgs(rootFsType, ethDeviceIP, ethDeviceGateway, ethDeviceMask string) error {
+func (u *Unikraft) configureUnikraftArgs(rootFsType string, net types.NetDevParams, hostname string) error {
 	setCompatArgs := func() {
-		u.Net.Address = "netdev.ipv4_addr=" + ethDeviceIP
-		u.Net.Gateway = "netdev.ipv4_gw_addr=" + ethDeviceGateway
-		u.Net.Mask = "netdev.ipv4_subnet_mask=" + ethDeviceMask
-		// TODO: We need to add support for actual block devices (e.g. virtio-blk)
-		// and sharedfs or any other Unikraft related ways to pass data to guest.
+		u.Net.Address = "netdev.ipv4_addr=" + net.IP
+		u.Net.Gateway = "netdev.ipv4_gw_addr=" + net.Gateway
+		u.Net.Mask = "netdev.ipv4_subnet_mask=" + net.Mask
+		// Old versions of Unikraft do not support fstab and hence
+		// block devices, virtiofs or any other way to pass data to
+		// the guest, besides initrd.
 		if rootFsType == "initrd" {
 			u.VFS.RootFS = "vfs.rootfs=" + "initrd"
 		} else {
 			u.VFS.RootFS = ""
 		}
+		u.Blk = nil
 	}
 
 	setCurrentArgs := func() {
-		u.Net.Address = "netdev.ip=" + ethDeviceIP + "/24:" + ethDeviceGateway + ":8.8.8.8"
-		switch rootFsType {
-		case "initrd":
-			// TODO: This needs better handling. We need to revisit this
-			// when we better understand all the available options for
-			// passing info inside unikraft unikernels.
-			u.VFS.RootFS = "vfs.fstab=[ \"initrd0:/:extract:::\" ]"
-		case "9pfs":
-			u.VFS.RootFS = "vfs.fstab=[ \"fs0:/:9pfs:::\" ]"
-		default:
-			u.VFS.RootFS = ""
+		u.Net.Address = netdevIP(net, hostname)
+		u.VFS.RootFS = ""
+		fstab := u.fstabEntries(rootFsType)
+		if len(fstab) > 0 {
+			u.VFS.RootFS = "vfs.fstab=[ " + strings.Join(fstab, " ") + " ]"
 		}
 	}
 
-	if u.Version == "" {
+	compat, err := isCompatVersion(u.Version)
+	if errors.Is(err, ErrUndefinedVersion) || errors.Is(err, ErrVersionParsing) {
 		setCurrentArgs()
-		return ErrUndefinedVersion
+		return err
 	}
-
-	unikernelVersion, err := version.NewVersion(u.Version)
-	if err != nil {
-		setCurrentArgs()
-		return ErrVersionParsing
-	}
-
-	targetVersion, err := version.NewVersion(UnikraftCompatVersion)
 	if err != nil {
-		return fmt.Errorf("failed to parse default version: %w", err)
+		return err
 	}
 
-	if unikernelVersion.GreaterThanOrEqual(targetVersion) {
+	if !compat {
 		setCurrentArgs()
 	} else {
 		setCompatArgs()
@@ -166,7 +219,67 @@ func (u *Unikraft) configureUnikraftArgs(rootFsType, ethDeviceIP, ethDeviceGatew
 	return nil
 }
 
-func newUnikraft() *Unikraft {
+// netdevIP returns the netdev.ip option of Unikraft, which has the format
+// "netdev.ip=ip/cidr:gateway:dns0:dns1:hostname".
+func netdevIP(net types.NetDevParams, hostname string) string {
+	cidr, err := subnetMaskToCIDR(net.Mask)
+	if err != nil {
+		// Keep the previous default for guests without a valid mask
+		cidr = 24
+	}
+	dns0 := defaultUnikraftDNS
+	dns1 := ""
+	if len(net.DNS) > 0 {
+		dns0 = net.DNS[0]
+	}
+	if len(net.DNS) > 1 {
+		dns1 = net.DNS[1]
+	}
+
+	fields := []string{net.IP + "/" + strconv.Itoa(cidr), net.Gateway, dns0, dns1, hostname}
+	// Unikraft uses its defaults for the trailing fields which are omitted
+	for len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+
+	return "netdev.ip=" + strings.Join(fields, ":")
+}
+
+// fstabEntries returns the entries of the fstab of Unikraft for the rootfs
+// and the block devices of the guest. Each entry has the format
+// "device:mountpoint:driver:flags:opts:ukopts", where the device of a block
+// device is its ID in the monitor.
+func (u *Unikraft) fstabEntries(rootFsType string) []string {
+	var fstab []string
+	switch rootFsType {
+	case "initrd":
+		// TODO: This needs better handling. We need to revisit this
+		// when we better understand all the available options for
+		// passing info inside unikraft unikernels.
+		fstab = append(fstab, "\"initrd0:/:extract:::\"")
+	case "9pfs":
+		fstab = append(fstab, "\"fs0:/:9pfs:::\"")
+	case "virtiofs":
+		fstab = append(fstab, "\"fs0:/:virtiofs:::\"")
+	}
+	for _, aBlock := range u.Blk {
+		// Unikraft can not mount a block without a known filesystem,
+		// such as the block images of the annotations without a fsType.
+		if aBlock.MountPoint == "" || !slices.Contains(unikraftBlockFS, aBlock.FsType) {
+			continue
+		}
+		fstab = append(fstab, fmt.Sprintf("\"%s:%s:%s:::\"", monitorBlockID(u.Monitor, aBlock.ID), aBlock.MountPoint, aBlock.FsType))
+	}
+
+	return fstab
+}
+
+// The filesystems of block devices that Unikraft can mount through vfs.fstab,
+// as long as the unikernel is built with their driver (e.g. lwext4).
+var unikraftBlockFS = []string{"ext4"}
+
+func newUnikraft(version string) *Unikraft {
 	unikraftStruct := new(Unikraft)
+	unikraftStruct.Version = version
 	return unikraftStruct
 }
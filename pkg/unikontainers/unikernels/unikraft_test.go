@@ -0,0 +1,182 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikernels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+)
+
+func TestUnikraftFstab(t *testing.T) {
+	t.Parallel()
+	blocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/rootfs.img", MountPoint: "/", FsType: "ext4"},
+		{ID: "vol1", Source: "/dev/sdb1", MountPoint: "/data", FsType: "ext4"},
+	}
+	// Blocks with an unknown or unsupported filesystem
+	otherBlocks := []types.BlockDevParams{
+		{ID: "rootfs", Source: "/rootfs.img", MountPoint: "/"},
+		{ID: "vol1", Source: "/dev/sdb1", MountPoint: "/data", FsType: "xfs"},
+	}
+
+	tests := []struct {
+		name       string
+		monitor    string
+		version    string
+		rootfsType string
+		block      []types.BlockDevParams
+		fstab      string
+		blkArgs    []types.MonitorBlockArgs
+	}{
+		{
+			name:       "block rootfs and volume on qemu",
+			monitor:    "qemu",
+			version:    "0.18.0",
+			rootfsType: "block",
+			block:      blocks,
+			fstab:      `vfs.fstab=[ "rootfs:/:ext4:::" "vol1:/data:ext4:::" ]`,
+			blkArgs: []types.MonitorBlockArgs{
+				{ID: "rootfs", Path: "/rootfs.img"},
+				{ID: "vol1", Path: "/dev/sdb1"},
+			},
+		},
+		{
+			name:       "block rootfs and volume on firecracker",
+			monitor:    "firecracker",
+			version:    "0.18.0",
+			rootfsType: "block",
+			block:      blocks,
+			fstab:      `vfs.fstab=[ "FCrootfs:/:ext4:::" "FCvol1:/data:ext4:::" ]`,
+			blkArgs: []types.MonitorBlockArgs{
+				{ID: "FCrootfs", Path: "/rootfs.img"},
+				{ID: "FCvol1", Path: "/dev/sdb1"},
+			},
+		},
+		{
+			name:       "virtiofs rootfs and volume",
+			monitor:    "qemu",
+			version:    "0.18.0",
+			rootfsType: "virtiofs",
+			block:      blocks[1:],
+			fstab:      `vfs.fstab=[ "fs0:/:virtiofs:::" "vol1:/data:ext4:::" ]`,
+			blkArgs:    []types.MonitorBlockArgs{{ID: "vol1", Path: "/dev/sdb1"}},
+		},
+		{
+			name:       "blocks without a supported filesystem",
+			monitor:    "qemu",
+			version:    "0.18.0",
+			rootfsType: "block",
+			block:      otherBlocks,
+			fstab:      "",
+			blkArgs: []types.MonitorBlockArgs{
+				{ID: "rootfs", Path: "/rootfs.img"},
+				{ID: "vol1", Path: "/dev/sdb1"},
+			},
+		},
+		{
+			name:       "9pfs rootfs",
+			monitor:    "qemu",
+			version:    "0.18.0",
+			rootfsType: "9pfs",
+			fstab:      `vfs.fstab=[ "fs0:/:9pfs:::" ]`,
+		},
+		{
+			name:       "no rootfs",
+			monitor:    "qemu",
+			version:    "0.18.0",
+			rootfsType: "",
+			fstab:      "",
+		},
+		{
+			name:       "old version ignores block devices",
+			monitor:    "qemu",
+			version:    "0.15.0",
+			rootfsType: "block",
+			block:      blocks,
+			fstab:      "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			u := newUnikraft("")
+			err := u.Init(types.UnikernelParams{
+				Monitor: tc.monitor,
+				Version: tc.version,
+				Block:   tc.block,
+				Rootfs:  types.RootfsParams{Type: tc.rootfsType},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.fstab, u.VFS.RootFS)
+			assert.Equal(t, tc.blkArgs, u.MonitorBlockCli())
+		})
+	}
+}
+
+func TestUnikraftSupports(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		version string
+		fstab   bool
+	}{
+		{version: "0.15.0", fstab: false},
+		{version: UnikraftCompatVersion, fstab: true},
+		{version: "0.18.0", fstab: true},
+		{version: "", fstab: true},
+		{version: "invalid", fstab: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+			u, err := New(UnikraftUnikernel, tc.version)
+			require.NoError(t, err)
+			assert.Equal(t, tc.fstab, u.SupportsBlock())
+			assert.Equal(t, tc.fstab, u.SupportsFS("virtiofs"))
+			assert.True(t, u.SupportsFS("initrd"))
+			assert.True(t, u.SupportsFS("9pfs"))
+			assert.False(t, u.SupportsFS("ext4"))
+		})
+	}
+}
+
+func TestUnikraftNetdevIP(t *testing.T) {
+	t.Parallel()
+	net := types.NetDevParams{
+		IP:      "10.244.1.5",
+		Mask:    "255.255.0.0",
+		Gateway: "10.244.0.1",
+	}
+
+	assert.Equal(t, "netdev.ip=10.244.1.5/16:10.244.0.1:8.8.8.8", netdevIP(net, ""))
+	assert.Equal(t, "netdev.ip=10.244.1.5/16:10.244.0.1:8.8.8.8::nginx", netdevIP(net, "nginx"))
+
+	net.DNS = []string{"10.96.0.10"}
+	assert.Equal(t, "netdev.ip=10.244.1.5/16:10.244.0.1:10.96.0.10", netdevIP(net, ""))
+
+	net.DNS = []string{"10.96.0.10", "1.1.1.1", "9.9.9.9"}
+	assert.Equal(t, "netdev.ip=10.244.1.5/16:10.244.0.1:10.96.0.10:1.1.1.1:nginx", netdevIP(net, "nginx"))
+
+	net.Mask = "invalid"
+	assert.Equal(t, "netdev.ip=10.244.1.5/24:10.244.0.1:10.96.0.10:1.1.1.1", netdevIP(net, ""))
+
+	u := newUnikraft("")
+	require.NoError(t, u.Init(types.UnikernelParams{Version: "0.18.0", Net: net, Hostname: "nginx"}))
+	assert.Equal(t, "netdev.ip=10.244.1.5/24:10.244.0.1:10.96.0.10:1.1.1.1:nginx", u.Net.Address)
+}
@@ -42,6 +42,18 @@ func subnetMaskToCIDR(subnetMask string) (int, error) {
 	return cidr, nil
 }
 
+// monitorBlockID returns the ID of a block device in the given monitor.
+// Firecracker makes the drive with the "rootfs" ID the root device and
+// appends the respective root= option to the command line of the guest.
+// Since the guests mount their rootfs by themselves, the IDs get the "FC"
+// prefix on Firecracker, so that no drive becomes the root device.
+func monitorBlockID(monitor string, id string) string {
+	if monitor == "firecracker" {
+		return "FC" + id
+	}
+	return id
+}
+
 func createFile(path string, content string) error {
 	file, err := os.Create(path)
 	if err != nil {
@@ -25,11 +25,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 
 	"github.com/urunc-dev/urunc/pkg/network"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/agent"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/initrd"
 	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
@@ -44,14 +45,21 @@ import (
 
 const (
 	monitorRootfsDirName     string = "monRootfs"
+	monitorCtrlDirName       string = "monitor"
 	containerRootfsMountPath string = "/cntrRootfs"
 )
 
+// StatePaused is the status of a container whose guest has been paused.
+// It is not part of the OCI runtime spec, but it is the status that runc
+// reports for paused containers and the one containerd expects.
+const StatePaused specs.ContainerState = "paused"
+
 var uniklog = logrus.WithField("subsystem", "unikontainers")
 
 var ErrQueueProxy = errors.New("this a queue proxy container")
 var ErrNotUnikernel = errors.New("this is not a unikernel container")
 var ErrNotExistingNS = errors.New("the namespace does not exist")
+var ErrExecNotSupported = errors.New("exec is not supported")
 
 // Unikontainer holds the data necessary to create, manage and delete unikernel containers
 type Unikontainer struct {
@@ -90,6 +98,10 @@ func New(bundlePath string, containerID string, rootDir string, cfg *UruncConfig
 	confMap := config.Map()
 
 	maps.Copy(confMap, cfg.Map())
+	// Record the monitor that will actually run the unikernel, so that
+	// all subsequent commands use it.
+	usable := fallbackUsable(bundlePath, spec, config.UnikernelType, confMap, cfg)
+	confMap[annotHypervisor] = selectMonitor(config.Hypervisor, cfg.Monitors, usable)
 	containerDir := filepath.Join(rootDir, containerID)
 	state := &specs.State{
 		Version:     spec.Version,
@@ -133,6 +145,38 @@ func Get(containerID string, rootDir string) (*Unikontainer, error) {
 	return u, nil
 }
 
+// List retrieves the data of all the unikernel containers which have their
+// state.json file under rootDir. Any directories that do not contain the state
+// of a unikernel container are skipped.
+func List(rootDir string) ([]*Unikontainer, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read root directory %s: %w", rootDir, err)
+	}
+
+	ukontainers := make([]*Unikontainer, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		u, err := Get(entry.Name(), rootDir)
+		if err != nil {
+			// Skip directories of non-unikernel containers and
+			// directories without a state.json file
+			if !errors.Is(err, ErrNotUnikernel) && !errors.Is(err, os.ErrNotExist) {
+				uniklog.WithError(err).Warnf("failed to load the state of %s", entry.Name())
+			}
+			continue
+		}
+		ukontainers = append(ukontainers, u)
+	}
+
+	return ukontainers, nil
+}
+
 // InitialSetup sets the Unikernel status as creating,
 // creates the Unikernel base directory and
 // saves the state.json file with the current Unikernel state
@@ -164,6 +208,22 @@ func (u *Unikontainer) SetRunningState() error {
 	return u.saveContainerState()
 }
 
+// UpdateStatus checks if the process of a created, running or paused
+// Unikernel is still alive. If the process has exited, the Unikernel status
+// is set as stopped and the new state is saved in state.json
+func (u *Unikontainer) UpdateStatus() error {
+	switch u.State.Status {
+	case specs.StateCreated, specs.StateRunning, StatePaused:
+	default:
+		return nil
+	}
+	if u.isRunning() {
+		return nil
+	}
+	u.State.Status = specs.StateStopped
+	return u.saveContainerState()
+}
+
 func (u *Unikontainer) SetupNet() (types.NetDevParams, error) {
 	networkType := u.getNetworkType()
 	uniklog.WithField("network type", networkType).Debug("Retrieved network type")
@@ -210,7 +270,8 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 
 	// unikernel
 	unikernelType := u.State.Annotations[annotType]
-	unikernel, err := unikernels.New(unikernelType)
+	unikernelVersion := u.State.Annotations[annotVersion]
+	unikernel, err := unikernels.New(unikernelType, unikernelVersion)
 	if err != nil {
 		return err
 	}
@@ -222,9 +283,6 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		return err
 	}
 
-	// unikernelParams
-	unikernelVersion := u.State.Annotations[annotVersion]
-
 	// ExecArgs
 	unikernelPath := u.State.Annotations[annotBinary]
 	initrdPath := u.State.Annotations[annotInitrd]
@@ -283,15 +341,19 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 	// UnikernelParams
 	// populate unikernel params
 	unikernelParams := types.UnikernelParams{
-		CmdLine:  u.Spec.Process.Args,
+		CmdLine:  u.guestCmdLine(),
 		EnvVars:  u.Spec.Process.Env,
 		Monitor:  vmmType,
 		Version:  unikernelVersion,
+		Hostname: u.Spec.Hostname,
 		ProcConf: procAttrs,
 	}
-	if len(unikernelParams.CmdLine) == 0 {
-		unikernelParams.CmdLine = strings.Fields(u.State.Annotations[annotCmdLine])
+	for _, feature := range strings.Split(u.State.Annotations[annotUrunitFeats], ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			unikernelParams.UrunitFeatures = append(unikernelParams.UrunitFeatures, feature)
+		}
 	}
+	unikernelParams.Solo5Devs = solo5Devices(vmmType, filepath.Join(rootfsDir, unikernelPath))
 
 	// handle network
 	netArgs, err := u.SetupNet()
@@ -301,6 +363,9 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 	}
 	metrics.Capture(m.TS16)
 	withTUNTAP := netArgs.IP != ""
+	if withTUNTAP {
+		netArgs.DNS = guestDNS(u.State.Annotations, u.Spec.Mounts)
+	}
 
 	// UnikernelParams
 	unikernelParams.Net = netArgs
@@ -327,6 +392,7 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		uniklog.Errorf("could not choose guest rootfs: %v", err)
 		return err
 	}
+	rootfsParams.ReadOnly = u.Spec.Root.Readonly
 
 	// Prepare Monitor rootfs
 	// Make sure that rootfs is mounted with the correct propagation
@@ -342,24 +408,42 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 	if err != nil {
 		return err
 	}
-	// TODO: Add support for using both an existing
-	// block based snapshot of the container's rootfs
-	// and an auxiliary block image placed in the container's image
-	// Currently if a block Image is present in the container's image, then
-	// we will just use this image.
+	// The volumes of the container which are backed by a block device
+	// or a disk image get attached to the guest as block devices, as long
+	// as the guest mounts them. The rest of the volumes get passed to the
+	// guest along with its rootfs.
+	blockVolumes := []types.BlockDevParams{}
+	mounts := u.Spec.Mounts
+	if unikernel.SupportsBlock() {
+		maxVolumes := maxBlockVolumes(unikernel, unikernelParams, rootfsParams)
+		diskVolumes, err := parseDiskVolumes(u.State.Annotations[annotDiskVolumes])
+		if err != nil {
+			return err
+		}
+		blockVolumes, mounts, err = getBlockVolumes(rootfsParams.MonRootfs, u.Spec.Mounts, unikernel, maxVolumes, diskVolumes)
+		if err != nil {
+			uniklog.Errorf("could not setup block volumes: %v", err)
+			return err
+		}
+	}
+
+	// The block devices of the guest are, in order, the block-based rootfs
+	// (either the block-based snapshot of the container's rootfs or a block
+	// image of the container's image), the block volumes and the block
+	// images of the annotations, which get mounted along with the rootfs.
 	blockArgs := []types.BlockDevParams{}
 	sharedfsArgs := types.SharedfsParams{}
 	tmpfsSize := "65536k"
 	switch rootfsParams.Type {
 	case "block":
-		blockArgs, err = handleBlockBasedRootfs(rootfsParams, unikernel, unikernelType, unikernelPath, uruncJSONFilename, initrdPath, u.Spec.Mounts)
+		blockArgs, err = handleBlockBasedRootfs(rootfsParams, unikernelType, unikernelPath, uruncJSONFilename, initrdPath, mounts)
 		if err != nil {
 			uniklog.Errorf("could not setup block based rootfs: %v", err)
 			return err
 		}
 	case "initrd":
 		initrdHostFullPath := filepath.Join(rootfsParams.MonRootfs, rootfsParams.Path)
-		err = initrd.CopyFileMountsToInitrd(initrdHostFullPath, u.Spec.Mounts)
+		err = initrd.CopyFileMountsToInitrd(initrdHostFullPath, mounts)
 		if err != nil {
 			uniklog.Errorf("could not update guest's initrd: %v", err)
 			return err
@@ -368,7 +452,7 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		tmpfsSize = chooseTmpfsSize(vmmArgs.MemSizeB)
 		fallthrough
 	case "9pfs":
-		err = setupSharedfsBasedRootfs(rootfsParams, virtiofsdConfig.Path, u.Spec.Mounts)
+		err = setupSharedfsBasedRootfs(rootfsParams, virtiofsdConfig.Path, mounts)
 		if err != nil {
 			return err
 		}
@@ -377,9 +461,11 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		vmmArgs.InitrdPath = adjustPathsForSharedfs(vmmArgs.InitrdPath)
 		sharedfsArgs.Path = containerRootfsMountPath
 		sharedfsArgs.Type = rootfsParams.Type
+		sharedfsArgs.ReadOnly = rootfsParams.ReadOnly
 	default:
 		uniklog.Debug("No rootfs for guest")
 	}
+	blockArgs = append(blockArgs, blockVolumes...)
 	unikernelParams.Rootfs = rootfsParams
 
 	err = createTmpfs(rootfsParams.MonRootfs, "/tmp",
@@ -388,6 +474,12 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 	if err != nil {
 		return err
 	}
+
+	err = setupControlDir(filepath.Join(u.BaseDir, monitorCtrlDirName), rootfsParams.MonRootfs,
+		u.Spec.Process.User.UID, u.Spec.Process.User.GID)
+	if err != nil {
+		return err
+	}
 	metrics.Capture(m.TS17)
 
 	blockFromAnnot, err := handleExplicitBlockImage(u.State.Annotations[annotBlock],
@@ -396,14 +488,26 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		return err
 	}
 	if blockFromAnnot.Source != "" && blockFromAnnot.MountPoint != "/" {
-		// TODO: Add proper support for multiple block Images from the container's
-		// image. This requires adding more annotations too.
 		blockFromAnnot.ID = "annot_vol"
 		blockArgs = append(blockArgs, blockFromAnnot)
 	}
+	blocksFromAnnot, err := handleExplicitBlockImages(u.State.Annotations[annotBlocks])
+	if err != nil {
+		return err
+	}
+	blockArgs = append(blockArgs, blocksFromAnnot...)
+	err = setupDiskFormats(rootfsParams.MonRootfs, blockArgs, vmm, u.UruncCfg.ExtraBins["qemu-img"])
+	if err != nil {
+		uniklog.Errorf("could not setup the disk images of the guest: %v", err)
+		return err
+	}
 
 	// unikernelParams
 	unikernelParams.Block = blockArgs
+	if declared := len(unikernelParams.Solo5Devs.Block); declared > 0 && len(blockArgs) > declared {
+		uniklog.Warnf("the unikernel declares %d block devices, ignoring %d of the %d block devices",
+			declared, len(blockArgs)-declared, len(blockArgs))
+	}
 
 	// ExecArgs
 	vmmArgs.Sharedfs = sharedfsArgs
@@ -435,6 +539,24 @@ func (u *Unikontainer) Exec(metrics m.Writer) error {
 		vmmArgs.VSockDevID = idToGuestCID(u.State.ID)
 	}
 
+	// Guest agent setup
+	agentPort := u.agentVSockPort()
+	// QEMU and crosvm need the vhost-vsock device, which is already
+	// available in the monitor's rootfs if vAccel uses vsock.
+	usesVhostVSock := vmmType == string(hypervisors.QemuVmm) || vmmType == string(hypervisors.CrosvmVmm)
+	if agentPort != 0 && usesVhostVSock && vmmArgs.VAccelType != "vsock" {
+		err = setupDev(rootfsParams.MonRootfs, "/dev/vhost-vsock")
+		if err != nil {
+			uniklog.Warnf("failed to setup vsock device, exec will not be available: %v", err)
+			agentPort = 0
+		}
+	}
+	if agentPort != 0 {
+		unikernelParams.AgentPort = agentPort
+		vmmArgs.AgentVSock = true
+		vmmArgs.VSockDevID = idToGuestCID(u.State.ID)
+	}
+
 	// unikernel
 	err = unikernel.Init(unikernelParams)
 	if err == unikernels.ErrUndefinedVersion || err == unikernels.ErrVersionParsing {
@@ -531,9 +653,41 @@ func setupUser(user specs.User) error {
 	return nil
 }
 
-// Kill stops the VMM process, first by asking the VMM struct to stop
-// and consequently by killing the process described in u.State.Pid
-func (u *Unikontainer) Kill() error {
+// Kill delivers the given signal to the unikernel container. Since the init
+// process of the container is the monitor, the signal gets mapped to the
+// respective monitor action:
+//   - SIGTERM, SIGINT and SIGPWR ask the guest to shut down gracefully
+//   - SIGKILL terminates the monitor immediately
+//   - SIGSTOP and SIGCONT pause and resume the guest, if the monitor supports
+//     it. Stopping a paused or continuing a running guest does nothing.
+//   - any other signal is forwarded to the monitor process
+//
+// If all is set, the signal is also sent to the helper processes of the
+// container (e.g. virtiofsd).
+func (u *Unikontainer) Kill(sig unix.Signal, all bool) error {
+	switch {
+	case sig == unix.SIGSTOP && u.supportsPause():
+		if u.State.Status == StatePaused {
+			return nil
+		}
+		return u.Pause()
+	case sig == unix.SIGCONT && u.supportsPause():
+		if u.State.Status != StatePaused {
+			return nil
+		}
+		return u.Resume()
+	case !isTerminatingSignal(sig):
+		return u.signal(sig, all)
+	}
+
+	// A paused guest can not handle a graceful shutdown request
+	if u.State.Status == StatePaused && sig != unix.SIGKILL {
+		err := u.Resume()
+		if err != nil {
+			uniklog.Warnf("failed to resume %s before stopping it: %v", u.State.ID, err)
+		}
+	}
+
 	// Try to join the Network namespace of the monitor before killing it.
 	// If we kill it there might be no process inside the namespace and hence
 	// the namespace gets destroyed.
@@ -549,16 +703,33 @@ func (u *Unikontainer) Kill() error {
 		return fmt.Errorf("failed to join sandbox netns: %v", err)
 	}
 
+	// Collect the helper processes before stopping the monitor, since
+	// they get reparented as soon as the monitor exits.
+	var helpers []int
+	if all {
+		helpers, err = descendantPids(u.State.Pid)
+		if err != nil {
+			uniklog.Warnf("failed to get the helper processes of %s: %v", u.State.ID, err)
+		}
+	}
+
 	// get a new vmm
 	vmmType := u.State.Annotations[annotHypervisor]
 	vmm, err := hypervisors.NewVMM(hypervisors.VmmType(vmmType), u.UruncCfg.Monitors)
 	if err != nil {
 		return err
 	}
-	err = vmm.Stop(u.State.Pid)
+	// Guests which ignore the power off request of the monitor would only
+	// get killed after the shutdown timeout expires.
+	if sig == unix.SIGKILL || !unikernels.HandlesPowerOff(u.UnikernelType(), vmmType) {
+		err = vmm.Kill(u.State.Pid)
+	} else {
+		err = vmm.Stop(u.State.Pid)
+	}
 	if err != nil {
 		return err
 	}
+	signalProcesses(helpers, sig)
 
 	// TODO: tap0_urunc should not be hardcoded
 	err = network.Cleanup("tap0_urunc")
@@ -569,6 +740,176 @@ func (u *Unikontainer) Kill() error {
 	return nil
 }
 
+// Pause pauses the execution of the guest and sets the Unikernel status
+// as paused
+func (u *Unikontainer) Pause() error {
+	if u.State.Status != specs.StateRunning || !u.isRunning() {
+		return fmt.Errorf("container %s is not running", u.State.ID)
+	}
+	pauser, err := u.getVMMPauser()
+	if err != nil {
+		return err
+	}
+	err = pauser.Pause(u.State.Pid)
+	if err != nil {
+		return fmt.Errorf("failed to pause container %s: %w", u.State.ID, err)
+	}
+	u.State.Status = StatePaused
+	return u.saveContainerState()
+}
+
+// Resume resumes the execution of a paused guest and sets the Unikernel
+// status as running
+func (u *Unikontainer) Resume() error {
+	if u.State.Status != StatePaused {
+		return fmt.Errorf("container %s is not paused", u.State.ID)
+	}
+	pauser, err := u.getVMMPauser()
+	if err != nil {
+		return err
+	}
+	err = pauser.Resume(u.State.Pid)
+	if err != nil {
+		return fmt.Errorf("failed to resume container %s: %w", u.State.ID, err)
+	}
+	u.State.Status = specs.StateRunning
+	return u.saveContainerState()
+}
+
+// newVMM returns the monitor that pauses and resumes the guest. Tests
+// replace it with a stub monitor.
+var newVMM = hypervisors.NewVMM
+
+// getVMMPauser returns the monitor of the Unikernel, if it supports pausing
+// and resuming the guest.
+func (u *Unikontainer) getVMMPauser() (types.VMMPauser, error) {
+	vmmType := u.State.Annotations[annotHypervisor]
+	vmm, err := newVMM(hypervisors.VmmType(vmmType), u.UruncCfg.Monitors)
+	if err != nil {
+		return nil, err
+	}
+	pauser, ok := vmm.(types.VMMPauser)
+	if !ok {
+		return nil, fmt.Errorf("monitor %s does not support pause/resume", vmmType)
+	}
+
+	return pauser, nil
+}
+
+// supportsPause returns true if the monitor of the Unikernel supports
+// pausing and resuming the guest.
+func (u *Unikontainer) supportsPause() bool {
+	_, err := u.getVMMPauser()
+	return err == nil
+}
+
+// ExecProcess executes the given process inside the guest through the guest
+// agent and returns the exit status of the process. The stdio of the process
+// is connected to the given streams.
+func (u *Unikontainer) ExecProcess(proc agent.Process, stdio agent.Stdio) (int, error) {
+	if u.State.Status != specs.StateRunning || !u.isRunning() {
+		return -1, fmt.Errorf("container %s is not running", u.State.ID)
+	}
+	port := u.agentVSockPort()
+	if port == 0 && !agent.GuestSupport {
+		return -1, fmt.Errorf("%w: urunit does not implement the guest agent yet", ErrExecNotSupported)
+	}
+	if port == 0 {
+		return -1, fmt.Errorf("%w in %s guests over %s without urunit and the %s annotation",
+			ErrExecNotSupported, u.UnikernelType(), u.Hypervisor(), annotAgent)
+	}
+	vmmType := u.State.Annotations[annotHypervisor]
+	vmm, err := hypervisors.NewVMM(hypervisors.VmmType(vmmType), u.UruncCfg.Monitors)
+	if err != nil {
+		return -1, err
+	}
+	dialer, ok := vmm.(types.VMMVSockDialer)
+	if !ok {
+		return -1, fmt.Errorf("%w: monitor %s can not connect to the guest", ErrExecNotSupported, vmmType)
+	}
+	conn, err := dialer.DialVSock(u.State.Pid, port)
+	if err != nil {
+		return -1, fmt.Errorf("failed to connect to the agent of container %s: %w", u.State.ID, err)
+	}
+	defer conn.Close()
+
+	return agent.Exec(conn, proc, stdio)
+}
+
+// agentEnabled returns true if the container enables the guest agent
+func (u *Unikontainer) agentEnabled() bool {
+	value, ok := u.State.Annotations[annotAgent]
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		uniklog.Warnf("invalid value in %s annotation: %s", annotAgent, value)
+		return false
+	}
+
+	return enabled
+}
+
+// agentVSockPort returns the vsock port that the agent of the guest listens
+// on, or zero if the guest does not run an agent that urunc can reach.
+// Currently, only Linux guests with urunit over QEMU, Firecracker,
+// Cloud Hypervisor and crosvm have an agent, if the container enables it.
+func (u *Unikontainer) agentVSockPort() uint32 {
+	if !u.agentEnabled() || u.UnikernelType() != unikernels.LinuxUnikernel {
+		return 0
+	}
+	if !agent.GuestSupport {
+		uniklog.Warnf("ignoring %s, since urunit does not implement the guest agent yet", annotAgent)
+		return 0
+	}
+	switch hypervisors.VmmType(u.Hypervisor()) {
+	case hypervisors.QemuVmm, hypervisors.FirecrackerVmm, hypervisors.CloudHypervisorVmm, hypervisors.CrosvmVmm:
+	default:
+		return 0
+	}
+	if !unikernels.UsesUrunit(u.guestCmdLine()) {
+		return 0
+	}
+
+	return agent.DefaultVSockPort
+}
+
+// guestCmdLine returns the command line of the guest, as specified in the
+// container's process or, if it is empty, in the respective annotation.
+func (u *Unikontainer) guestCmdLine() []string {
+	if u.Spec.Process != nil && len(u.Spec.Process.Args) > 0 {
+		return u.Spec.Process.Args
+	}
+
+	return strings.Fields(u.State.Annotations[annotCmdLine])
+}
+
+// signal forwards the given signal to the monitor process and, if all is
+// set, to the helper processes of the container.
+func (u *Unikontainer) signal(sig unix.Signal, all bool) error {
+	if !u.isRunning() {
+		return fmt.Errorf("container %s is not running", u.State.ID)
+	}
+
+	var helpers []int
+	if all {
+		var err error
+		helpers, err = descendantPids(u.State.Pid)
+		if err != nil {
+			uniklog.Warnf("failed to get the helper processes of %s: %v", u.State.ID, err)
+		}
+	}
+
+	err := unix.Kill(u.State.Pid, sig)
+	if err != nil {
+		return fmt.Errorf("failed to send %s to the monitor process: %w", unix.SignalName(sig), err)
+	}
+	signalProcesses(helpers, sig)
+
+	return nil
+}
+
 // Delete removes the containers base directory and its contents
 func (u *Unikontainer) Delete() error {
 	var dirs []string
@@ -1119,11 +1460,39 @@ func (u *Unikontainer) SendMessage(message IPCMessage) error {
 	return nil
 }
 
+// Hypervisor returns the monitor which executes the unikernel
+func (u *Unikontainer) Hypervisor() string {
+	return u.State.Annotations[annotHypervisor]
+}
+
+// UnikernelType returns the type of the unikernel
+func (u *Unikontainer) UnikernelType() string {
+	return u.State.Annotations[annotType]
+}
+
+// Processes returns the pids of the processes that belong to the
+// unikernel container. The first pid is always the one of the monitor
+// process, followed by any helper processes that the monitor has spawned
+// (e.g. virtiofsd).
+func (u *Unikontainer) Processes() ([]int, error) {
+	if !u.isRunning() {
+		return nil, fmt.Errorf("container %s is not running", u.State.ID)
+	}
+
+	pids := []int{u.State.Pid}
+	helpers, err := descendantPids(u.State.Pid)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(pids, helpers...), nil
+}
+
 // isRunning returns true if the PID is alive or hedge.ListVMs returns our containerID
 func (u *Unikontainer) isRunning() bool {
-	vmmType := hypervisors.VmmType(u.State.Annotations[annotType])
+	vmmType := hypervisors.VmmType(u.State.Annotations[annotHypervisor])
 	if vmmType != hypervisors.HedgeVmm {
-		return syscall.Kill(u.State.Pid, syscall.Signal(0)) == nil
+		return isProcessAlive(u.State.Pid)
 	}
 	hedge := hypervisors.Hedge{}
 	state := hedge.VMState(u.State.ID)
@@ -0,0 +1,141 @@
+// Copyright (c) 2023-2026, Nubificus LTD
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unikontainers
+
+import (
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/agent"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/hypervisors"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/types"
+	"github.com/urunc-dev/urunc/pkg/unikontainers/unikernels"
+	"golang.org/x/sys/unix"
+)
+
+// newPausableUnikontainer returns a Unikontainer over a monitor which
+// supports pausing, without any running monitor process.
+func newPausableUnikontainer(status specs.ContainerState) *Unikontainer {
+	return &Unikontainer{
+		State: &specs.State{
+			ID:          "cntr",
+			Status:      status,
+			Pid:         -1,
+			Annotations: map[string]string{annotHypervisor: "qemu"},
+		},
+		UruncCfg: &UruncConfig{
+			Monitors: map[string]types.MonitorConfig{"qemu": {BinaryPath: "/bin/true"}},
+		},
+	}
+}
+
+func TestKillPauseSignals(t *testing.T) {
+	t.Run("SIGCONT on a running container", func(t *testing.T) {
+		t.Parallel()
+		u := newPausableUnikontainer(specs.StateRunning)
+		assert.True(t, u.supportsPause())
+		assert.NoError(t, u.Kill(unix.SIGCONT, false))
+		assert.Equal(t, specs.StateRunning, u.State.Status)
+	})
+
+	t.Run("SIGSTOP on a paused container", func(t *testing.T) {
+		t.Parallel()
+		u := newPausableUnikontainer(StatePaused)
+		assert.NoError(t, u.Kill(unix.SIGSTOP, false))
+		assert.Equal(t, StatePaused, u.State.Status)
+	})
+
+	t.Run("SIGSTOP without a monitor process fails", func(t *testing.T) {
+		t.Parallel()
+		u := newPausableUnikontainer(specs.StateRunning)
+		assert.ErrorContains(t, u.Kill(unix.SIGSTOP, false), "is not running")
+	})
+}
+
+// stubPauser is a monitor which records the pids that it pauses and resumes.
+type stubPauser struct {
+	types.VMM
+	paused  []int
+	resumed []int
+}
+
+func (s *stubPauser) Pause(pid int) error {
+	s.paused = append(s.paused, pid)
+	return nil
+}
+
+func (s *stubPauser) Resume(pid int) error {
+	s.resumed = append(s.resumed, pid)
+	return nil
+}
+
+// TestKillPausesMonitor does not run in parallel, since it replaces the
+// monitor of all the containers.
+func TestKillPausesMonitor(t *testing.T) {
+	pauser := &stubPauser{}
+	origNewVMM := newVMM
+	newVMM = func(hypervisors.VmmType, map[string]types.MonitorConfig) (types.VMM, error) {
+		return pauser, nil
+	}
+	t.Cleanup(func() { newVMM = origNewVMM })
+
+	// The test process acts as the running monitor process
+	u := newPausableUnikontainer(specs.StateRunning)
+	u.State.Pid = os.Getpid()
+	u.Spec = &specs.Spec{}
+	u.BaseDir = t.TempDir()
+
+	require.NoError(t, u.Kill(unix.SIGSTOP, false))
+	assert.Equal(t, []int{os.Getpid()}, pauser.paused)
+	assert.Equal(t, StatePaused, u.State.Status)
+
+	require.NoError(t, u.Kill(unix.SIGCONT, false))
+	assert.Equal(t, []int{os.Getpid()}, pauser.resumed)
+	assert.Equal(t, specs.StateRunning, u.State.Status)
+}
+
+func TestAgentVSockPort(t *testing.T) {
+	newUnikontainer := func(annotations map[string]string) *Unikontainer {
+		stateAnnotations := map[string]string{
+			annotType:       unikernels.LinuxUnikernel,
+			annotHypervisor: "qemu",
+		}
+		maps.Copy(stateAnnotations, annotations)
+		return &Unikontainer{
+			Spec: &specs.Spec{
+				Process: &specs.Process{Args: []string{"/urunit", "/bin/app"}},
+			},
+			State: &specs.State{Annotations: stateAnnotations},
+		}
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		assert.Zero(t, newUnikontainer(nil).agentVSockPort())
+		assert.Zero(t, newUnikontainer(map[string]string{annotAgent: "invalid"}).agentVSockPort())
+	})
+
+	t.Run("held until urunit implements the agent", func(t *testing.T) {
+		t.Parallel()
+		u := newUnikontainer(map[string]string{annotAgent: "true"})
+		assert.True(t, u.agentEnabled())
+		assert.False(t, agent.GuestSupport)
+		assert.Zero(t, u.agentVSockPort())
+	})
+}
@@ -80,16 +80,20 @@ func defaultTimestampsConfig() UruncTimestamps {
 
 func defaultMonitorsConfig() map[string]types.MonitorConfig {
 	return map[string]types.MonitorConfig{
-		"qemu":        {DefaultMemoryMB: 256, DefaultVCPUs: 1},
-		"hvt":         {DefaultMemoryMB: 256, DefaultVCPUs: 1},
-		"spt":         {DefaultMemoryMB: 256, DefaultVCPUs: 1},
-		"firecracker": {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"qemu":             {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"hvt":              {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"spt":              {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"sandbox":          {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"firecracker":      {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"cloud-hypervisor": {DefaultMemoryMB: 256, DefaultVCPUs: 1},
+		"crosvm":           {DefaultMemoryMB: 256, DefaultVCPUs: 1},
 	}
 }
 
 func defaultExtraBinConfig() map[string]types.ExtraBinConfig {
 	return map[string]types.ExtraBinConfig{
 		"virtiofsd": {Path: "/usr/libexec/virtiofsd", Options: "--cache always --sandbox none"},
+		"qemu-img":  {Path: "/usr/bin/qemu-img"},
 	}
 }
 
@@ -125,6 +129,8 @@ func (p *UruncConfig) Map() map[string]string {
 		cfgMap[prefix+"default_vcpus"] = strconv.FormatUint(uint64(hvCfg.DefaultVCPUs), 10)
 		cfgMap[prefix+"binary_path"] = hvCfg.BinaryPath
 		cfgMap[prefix+"data_path"] = hvCfg.DataPath
+		cfgMap[prefix+"shutdown_timeout"] = strconv.FormatUint(uint64(hvCfg.ShutdownTimeout), 10)
+		cfgMap[prefix+"fallback"] = strings.Join(hvCfg.Fallback, ",")
 	}
 	for eb, ebCfg := range p.ExtraBins {
 		prefix := "urunc_config.extra_binaries." + eb + "."
@@ -171,6 +177,14 @@ func UruncConfigFromMap(cfgMap map[string]string) *UruncConfig {
 			hvCfg.BinaryPath = val
 		case "data_path":
 			hvCfg.DataPath = val
+		case "shutdown_timeout":
+			if intVal, err := strconv.Atoi(val); err == nil && intVal > 0 {
+				hvCfg.ShutdownTimeout = uint(intVal)
+			}
+		case "fallback":
+			if val != "" {
+				hvCfg.Fallback = strings.Split(val, ",")
+			}
 		}
 		cfg.Monitors[hv] = hvCfg
 	}
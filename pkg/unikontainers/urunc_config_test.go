@@ -27,6 +27,7 @@ const (
 	testQemuVCPUsKey     = "urunc_config.monitors.qemu.default_vcpus"
 	testQemuBinaryKey    = "urunc_config.monitors.qemu.binary_path"
 	testQemuDataKey      = "urunc_config.monitors.qemu.data_path"
+	testQemuTimeoutKey   = "urunc_config.monitors.qemu.shutdown_timeout"
 	testHvtMemoryKey     = "urunc_config.monitors.hvt.default_memory_mb"
 	testVirtiofsdPathKey = "urunc_config.extra_binaries.virtiofsd.path"
 	testVirtiofsdOptsKey = "urunc_config.extra_binaries.virtiofsd.options"
@@ -52,10 +53,12 @@ func TestUruncConfigFromMap(t *testing.T) {
 	t.Run("single monitor with all fields", func(t *testing.T) {
 		t.Parallel()
 		cfgMap := map[string]string{
-			testQemuMemoryKey: "512",
-			testQemuVCPUsKey:  "2",
-			testQemuBinaryKey: testQemuBinaryPath,
-			testQemuDataKey:   testQemuDataPath,
+			testQemuMemoryKey:                     "512",
+			testQemuVCPUsKey:                      "2",
+			testQemuBinaryKey:                     testQemuBinaryPath,
+			testQemuDataKey:                       testQemuDataPath,
+			testQemuTimeoutKey:                    "10",
+			"urunc_config.monitors.qemu.fallback": "firecracker,hvt",
 		}
 
 		config := UruncConfigFromMap(cfgMap)
@@ -67,6 +70,8 @@ func TestUruncConfigFromMap(t *testing.T) {
 		assert.Equal(t, uint(2), qemuConfig.DefaultVCPUs)
 		assert.Equal(t, testQemuBinaryPath, qemuConfig.BinaryPath)
 		assert.Equal(t, testQemuDataPath, qemuConfig.DataPath)
+		assert.Equal(t, uint(10), qemuConfig.ShutdownTimeout)
+		assert.Equal(t, []string{"firecracker", "hvt"}, qemuConfig.Fallback)
 	})
 
 	t.Run("multiple monitors", func(t *testing.T) {
@@ -112,10 +117,11 @@ func TestUruncConfigFromMap(t *testing.T) {
 	t.Run("invalid or negative numeric values are ignored", func(t *testing.T) {
 		t.Parallel()
 		cfgMap := map[string]string{
-			testQemuMemoryKey: "invalid",
-			testQemuVCPUsKey:  "-5",
-			testQemuBinaryKey: testQemuBinaryPath,
-			testQemuDataKey:   testQemuDataPath,
+			testQemuMemoryKey:  "invalid",
+			testQemuVCPUsKey:   "-5",
+			testQemuBinaryKey:  testQemuBinaryPath,
+			testQemuDataKey:    testQemuDataPath,
+			testQemuTimeoutKey: "-1",
 			"urunc_config.monitors.qemu.field.extra.parts": "invalid",
 			testHvtMemoryKey: "512",
 		}
@@ -127,6 +133,7 @@ func TestUruncConfigFromMap(t *testing.T) {
 		qemuConfig := config.Monitors["qemu"]
 		assert.Equal(t, uint(256), qemuConfig.DefaultMemoryMB) // Default value for invalid input
 		assert.Equal(t, uint(1), qemuConfig.DefaultVCPUs)      // Default value for negative input
+		assert.Equal(t, uint(0), qemuConfig.ShutdownTimeout)   // Default value for negative input
 		assert.Equal(t, testQemuBinaryPath, qemuConfig.BinaryPath)
 		assert.Equal(t, testQemuDataPath, qemuConfig.DataPath)
 		assert.Contains(t, config.Monitors, "hvt")
@@ -339,6 +346,7 @@ func TestUruncConfigMap(t *testing.T) {
 			testQemuMemoryKey,
 			testQemuVCPUsKey,
 			testQemuBinaryKey,
+			testQemuTimeoutKey,
 			"urunc_config.monitors.hvt.default_memory_mb",
 			"urunc_config.monitors.hvt.default_vcpus",
 			"urunc_config.monitors.hvt.binary_path",
@@ -360,6 +368,7 @@ func TestUruncConfigMap(t *testing.T) {
 		assert.Equal(t, "256", cfgMap[testQemuMemoryKey])
 		assert.Equal(t, "1", cfgMap[testQemuVCPUsKey])
 		assert.Equal(t, "", cfgMap[testQemuBinaryKey])
+		assert.Equal(t, "0", cfgMap[testQemuTimeoutKey])
 		assert.Equal(t, "/usr/libexec/virtiofsd", cfgMap[testVirtiofsdPathKey])
 		assert.Equal(t, testVirtiofsdDefOpts, cfgMap[testVirtiofsdOptsKey])
 	})
@@ -372,6 +381,8 @@ func TestUruncConfigMap(t *testing.T) {
 					DefaultMemoryMB: 2048,
 					DefaultVCPUs:    4,
 					BinaryPath:      "/custom/path",
+					ShutdownTimeout: 30,
+					Fallback:        []string{"qemu", "spt"},
 				},
 			},
 			ExtraBins: map[string]types.ExtraBinConfig{
@@ -388,6 +399,8 @@ func TestUruncConfigMap(t *testing.T) {
 		assert.Equal(t, "2048", cfgMap["urunc_config.monitors.custom.default_memory_mb"])
 		assert.Equal(t, "4", cfgMap["urunc_config.monitors.custom.default_vcpus"])
 		assert.Equal(t, "/custom/path", cfgMap["urunc_config.monitors.custom.binary_path"])
+		assert.Equal(t, "30", cfgMap["urunc_config.monitors.custom.shutdown_timeout"])
+		assert.Equal(t, "qemu,spt", cfgMap["urunc_config.monitors.custom.fallback"])
 		assert.Equal(t, config.ExtraBins["custom"].Path, cfgMap["urunc_config.extra_binaries.custom.path"])
 		assert.Equal(t, config.ExtraBins["custom"].Options, cfgMap["urunc_config.extra_binaries.custom.options"])
 	})
@@ -438,11 +451,14 @@ func TestDefaultConfigs(t *testing.T) {
 		t.Parallel()
 		config := defaultMonitorsConfig()
 
-		assert.Len(t, config, 4)
+		assert.Len(t, config, 7)
 		assert.Contains(t, config, "qemu")
 		assert.Contains(t, config, "hvt")
 		assert.Contains(t, config, "spt")
 		assert.Contains(t, config, "firecracker")
+		assert.Contains(t, config, "cloud-hypervisor")
+		assert.Contains(t, config, "crosvm")
+		assert.Contains(t, config, "sandbox")
 
 		// Check default values for each monitor
 		for _, hvConfig := range config {
@@ -456,11 +472,14 @@ func TestDefaultConfigs(t *testing.T) {
 		t.Parallel()
 		config := defaultExtraBinConfig()
 
-		assert.Len(t, config, 1)
+		assert.Len(t, config, 2)
 		assert.Contains(t, config, "virtiofsd")
+		assert.Contains(t, config, "qemu-img")
 
 		assert.Equal(t, "/usr/libexec/virtiofsd", config["virtiofsd"].Path)
 		assert.Equal(t, testVirtiofsdDefOpts, config["virtiofsd"].Options)
+		assert.Equal(t, "/usr/bin/qemu-img", config["qemu-img"].Path)
+		assert.Empty(t, config["qemu-img"].Options)
 	})
 
 	t.Run("defaultUruncConfig", func(t *testing.T) {
@@ -472,8 +491,8 @@ func TestDefaultConfigs(t *testing.T) {
 		assert.False(t, config.Log.Syslog)
 		assert.False(t, config.Timestamps.Enabled)
 		assert.Equal(t, testTimestampsPath, config.Timestamps.Destination)
-		assert.Len(t, config.Monitors, 4)
-		assert.Len(t, config.ExtraBins, 1)
+		assert.Len(t, config.Monitors, 7)
+		assert.Len(t, config.ExtraBins, 2)
 	})
 
 	t.Run("defaultLogMetricsConfig", func(t *testing.T) {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -272,6 +273,105 @@ func resolveAgainstBase(base string, path string) (string, error) {
 	return resolvedPath, nil
 }
 
+// isProcessAlive checks if a process with the given pid exists and has not
+// exited yet. Zombie processes are considered dead, since they have already
+// exited and they just wait for their parent to reap them.
+func isProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := unix.Kill(pid, 0)
+	if errors.Is(err, unix.ESRCH) {
+		return false
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		// We could not read the stat file, so rely on the signal check
+		return !os.IsNotExist(err)
+	}
+	// The state of the process comes right after the command name, which
+	// is enclosed in parentheses and it might contain spaces.
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 || i+2 >= len(stat) {
+		return true
+	}
+	state := stat[i+2]
+
+	return state != 'Z' && state != 'X'
+}
+
+// descendantPids returns the pids of all the processes that are descendants
+// of the process with the given pid. It walks /proc and builds the process
+// tree based on the parent pid of each process.
+func descendantPids(pid int) ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc: %w", err)
+	}
+
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		p, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			// The process might have exited in the meantime
+			continue
+		}
+		// The parent pid is the second field after the command name,
+		// which is enclosed in parentheses and it might contain spaces.
+		i := bytes.LastIndexByte(stat, ')')
+		if i < 0 {
+			continue
+		}
+		fields := strings.Fields(string(stat[i+1:]))
+		if len(fields) < 2 {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		children[ppid] = append(children[ppid], p)
+	}
+
+	var pids []int
+	queue := children[pid]
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		pids = append(pids, p)
+		queue = append(queue, children[p]...)
+	}
+
+	return pids, nil
+}
+
+// isTerminatingSignal returns true if the signal should result in the
+// termination of the monitor process.
+func isTerminatingSignal(sig unix.Signal) bool {
+	switch sig {
+	case unix.SIGTERM, unix.SIGINT, unix.SIGPWR, unix.SIGKILL:
+		return true
+	default:
+		return false
+	}
+}
+
+// signalProcesses sends the given signal to all the processes in pids,
+// ignoring any processes which have already exited.
+func signalProcesses(pids []int, sig unix.Signal) {
+	for _, pid := range pids {
+		err := unix.Kill(pid, sig)
+		if err != nil && !errors.Is(err, unix.ESRCH) {
+			uniklog.Warnf("failed to send %s to pid %d: %v", unix.SignalName(sig), pid, err)
+		}
+	}
+}
+
 func fileExists(fpath string) bool {
 	var fileInfo unix.Stat_t
 
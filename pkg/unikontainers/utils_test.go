@@ -17,12 +17,14 @@ package unikontainers
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 )
 
 func TestWritePidFile(t *testing.T) {
@@ -364,3 +366,52 @@ func TestLoadSpec(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to parse specification json", "Expected specific error message")
 	})
 }
+
+func TestIsProcessAlive(t *testing.T) {
+	t.Run("current process is alive", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, isProcessAlive(os.Getpid()), "Expected the current process to be alive")
+	})
+
+	t.Run("invalid pids are not alive", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, isProcessAlive(-1), "Expected pid -1 to not be alive")
+		assert.False(t, isProcessAlive(0), "Expected pid 0 to not be alive")
+	})
+
+	t.Run("exited process is not alive", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command("true")
+		err := cmd.Run()
+		assert.NoError(t, err)
+		assert.False(t, isProcessAlive(cmd.Process.Pid), "Expected reaped process to not be alive")
+	})
+}
+
+func TestDescendantPids(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	err := cmd.Start()
+	assert.NoError(t, err)
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	pids, err := descendantPids(os.Getpid())
+	assert.NoError(t, err)
+	assert.Contains(t, pids, cmd.Process.Pid, "Expected the child process to be a descendant")
+
+	pids, err = descendantPids(cmd.Process.Pid)
+	assert.NoError(t, err)
+	assert.Empty(t, pids, "Expected no descendants for a process without children")
+}
+
+func TestIsTerminatingSignal(t *testing.T) {
+	t.Parallel()
+	for _, sig := range []unix.Signal{unix.SIGTERM, unix.SIGINT, unix.SIGPWR, unix.SIGKILL} {
+		assert.True(t, isTerminatingSignal(sig), "Expected %s to terminate the monitor", unix.SignalName(sig))
+	}
+	for _, sig := range []unix.Signal{unix.SIGSTOP, unix.SIGCONT, unix.SIGHUP, unix.SIGUSR1} {
+		assert.False(t, isTerminatingSignal(sig), "Expected %s to be forwarded to the monitor", unix.SignalName(sig))
+	}
+}
@@ -38,23 +38,23 @@ func idToGuestCID(id string) int {
 
 // isValidVSockAddress validates a vsock address string and ensures
 // it matches the expected format for the selected hypervisor.
-// For firecracker, it also replaces the RPC address with the
+// For firecracker and cloud-hypervisor, it also replaces the RPC address with the
 // corresponding vsock address, and returns the directory path of the
 // unix socket, which must later be bind-mounted into the guest rootfs.
 func isValidVSockAddress(rpcAddress *string, hypervisor string) (bool, string, error) {
 	var regex *regexp.Regexp
 
 	switch hypervisor {
-	case "qemu":
+	case "qemu", "crosvm":
 		regex = regexp.MustCompile(`^vsock://2:\d+$`)
-	case "firecracker":
+	case "firecracker", "cloud-hypervisor":
 		regex = regexp.MustCompile(`^unix://(.*)/vaccel\.sock_(\d+)$`)
 	default:
 		return false, "", fmt.Errorf("unsupported hypervisor: %q", hypervisor)
 	}
 
 	if regex.MatchString(*rpcAddress) {
-		if hypervisor == "firecracker" {
+		if hypervisor == "firecracker" || hypervisor == "cloud-hypervisor" {
 			matches := regex.FindStringSubmatch(*rpcAddress)
 			if matches == nil {
 				return false, "", fmt.Errorf("failed to parse rpc address %q for %s", *rpcAddress, hypervisor)
@@ -71,7 +71,7 @@ func isValidVSockAddress(rpcAddress *string, hypervisor string) (bool, string, e
 // resolveVAccelConfig parses and validates vAccel-related annotations,
 // resolves the RPC address based on the selected hypervisor,
 // and returns the vAccel type (e.g., "vsock"), the unix socket path to be
-// bind-mounted (Firecracker only) and the normalized RPC address to be
+// bind-mounted (Firecracker and cloud-hypervisor only) and the normalized RPC address to be
 // exported to the guest.
 func resolveVAccelConfig(hypervisor string, annotations map[string]string) (string, string, string, error) {
 	var err error
@@ -104,7 +104,8 @@ func resolveVAccelConfig(hypervisor string, annotations map[string]string) (stri
 
 // prepareVSockEnvironment prepares all required vsock devices and mounts
 // for vAccel execution inside the guest. This includes /dev/vsock,
-// /dev/vhost-vsock, and (for Firecracker) binding the host unix socket.
+// /dev/vhost-vsock, and (for Firecracker and cloud-hypervisor) binding the
+// host unix socket.
 func prepareVSockEnvironment(monRootfs string, hypervisor string, vsockSocketPath string) error {
 	err := setupDev(monRootfs, "/dev/vsock")
 	if err != nil {
@@ -117,7 +118,7 @@ func prepareVSockEnvironment(monRootfs string, hypervisor string, vsockSocketPat
 	}
 
 	// bind mount the unix socket directory
-	if hypervisor == "firecracker" {
+	if hypervisor == "firecracker" || hypervisor == "cloud-hypervisor" {
 		err = fileFromHost(monRootfs, vsockSocketPath, "", unix.MS_BIND|unix.MS_PRIVATE, false)
 		if err != nil {
 			return err